@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote addresses encoding, decoding and validation
+
+package address
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+const (
+	// KeySize, size of a public key
+	KeySize = 32
+	// checksum bytes at the end of an address
+	checksumSize = 4
+)
+
+var (
+	// ErrInvalidLength, the decoded address doesn't have the size of its kind
+	ErrInvalidLength = errors.New("invalid address length")
+	// ErrInvalidChecksum, the address checksum doesn't match its content
+	ErrInvalidChecksum = errors.New("invalid address checksum")
+	// ErrWrongNetwork, the address prefix isn't the one of the expected network
+	ErrWrongNetwork = errors.New("address of another network")
+)
+
+// Network, address prefix of a network, the varint written at the start of its addresses
+type Network struct {
+	Name          string
+	AddressPrefix uint64
+}
+
+// Mainnet, Iridium network, addresses start with "ir"
+// the daemon has a single prefix, CRYPTONOTE_PUBLIC_ADDRESS_BASE58_PREFIX in src/CryptoNoteConfig.h, to keep in sync
+var Mainnet = Network{Name: "mainnet", AddressPrefix: 0x1cfa}
+
+// Networks, networks known by Parse
+var Networks = []Network{Mainnet}
+
+// PublicKey, a public key of an address
+type PublicKey [KeySize]byte
+
+func (key PublicKey) String() string {
+	return hex.EncodeToString(key[:])
+}
+
+// Address, a standard address : its network, public spend key and public view key
+type Address struct {
+	Network  Network
+	SpendKey PublicKey
+	ViewKey  PublicKey
+}
+
+// String, the Base58 address
+func (address *Address) String() string {
+	return encode(address.Network.AddressPrefix, address.keys())
+}
+
+// spend key followed by view key, as encoded
+func (address *Address) keys() []byte {
+	keys := make([]byte, 0, 2*KeySize)
+	keys = append(keys, address.SpendKey[:]...)
+	return append(keys, address.ViewKey[:]...)
+}
+
+// Decode, decodes an address of network
+func Decode(address string, network Network) (*Address, error) {
+	payload, err := decodeNetwork(address, network)
+	if err != nil {
+		return nil, err
+	}
+	return newAddress(network, payload)
+}
+
+// Parse, decodes an address of any of the known Networks
+func Parse(address string) (*Address, error) {
+	network, payload, err := parseNetwork(address)
+	if err != nil {
+		return nil, err
+	}
+	return newAddress(network, payload)
+}
+
+// Validate, returns nil when address is a valid address of network
+func Validate(address string, network Network) error {
+	_, err := Decode(address, network)
+	return err
+}
+
+func newAddress(network Network, payload []byte) (*Address, error) {
+	if len(payload) != 2*KeySize {
+		return nil, fmt.Errorf("%w : %d bytes of keys", ErrInvalidLength, len(payload))
+	}
+	address := &Address{Network: network}
+	copy(address.SpendKey[:], payload[:KeySize])
+	copy(address.ViewKey[:], payload[KeySize:])
+	return address, nil
+}
+
+// payload of an address of network
+func decodeNetwork(address string, network Network) ([]byte, error) {
+	prefix, payload, err := decode(address)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != network.AddressPrefix {
+		return nil, fmt.Errorf("%w : prefix %#x, %s expects %#x", ErrWrongNetwork, prefix, network.Name, network.AddressPrefix)
+	}
+	return payload, nil
+}
+
+// network and payload of an address of any of the known Networks
+func parseNetwork(address string) (Network, []byte, error) {
+	prefix, payload, err := decode(address)
+	if err != nil {
+		return Network{}, nil, err
+	}
+	for _, network := range Networks {
+		if prefix == network.AddressPrefix {
+			return network, payload, nil
+		}
+	}
+	return Network{}, nil, fmt.Errorf("%w : unknown prefix %#x", ErrWrongNetwork, prefix)
+}
+
+// Base58 of the varint prefix, the payload and the checksum
+func encode(prefix uint64, payload []byte) string {
+	data := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(payload)+checksumSize)
+	data = append(data[:binary.PutUvarint(data, prefix)], payload...)
+	checksum := cnhash.FastHash(data)
+	return EncodeBase58(append(data, checksum[:checksumSize]...))
+}
+
+// decodes the Base58 address, checks its checksum and returns its prefix and payload
+func decode(address string) (uint64, []byte, error) {
+	data, err := DecodeBase58(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) <= checksumSize {
+		return 0, nil, ErrInvalidLength
+	}
+	content, checksum := data[:len(data)-checksumSize], data[len(data)-checksumSize:]
+	expected := cnhash.FastHash(content)
+	if string(checksum) != string(expected[:checksumSize]) {
+		return 0, nil, ErrInvalidChecksum
+	}
+	prefix, size := binary.Uvarint(content)
+	if size <= 0 {
+		return 0, nil, fmt.Errorf("%w : invalid prefix", ErrInvalidLength)
+	}
+	return prefix, content[size:], nil
+}
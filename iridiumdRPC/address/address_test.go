@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote addresses tests
+package address
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// a Monero address, same encoding with the prefix 18
+const moneroAddress = "44AFFq5kSiGBoZ4NMDwYtN18obc8AemS33DBLWs3H7otXft3XjrpDtQGv7SqSsaBYBb98uNbr2VBBEt7f2wfn3RVGQBEP3A"
+
+var monero = Network{Name: "monero", AddressPrefix: 18}
+
+func testAddress(network Network) *Address {
+	address := &Address{Network: network}
+	for i := range address.SpendKey {
+		address.SpendKey[i] = byte(i)
+		address.ViewKey[i] = byte(255 - i)
+	}
+	return address
+}
+
+func TestDecode(t *testing.T) {
+	address, err := Decode(moneroAddress, monero)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if address.SpendKey.String() != "42f18fc61586554095b0799b5c4b6f00cdeb26a93b20540d366932c6001617b7" ||
+		address.ViewKey.String() != "5db35109fbba7d5f275fef4b9c49e0cc1c84b219ec6ff652fda54f89f7f63c88" {
+		t.Errorf("%sunexpected keys %s %s", er, address.SpendKey, address.ViewKey)
+	}
+	if address.String() != moneroAddress {
+		t.Errorf("%sre-encoded as %s", er, address)
+	}
+	t.Logf("%s%s", ok, address.SpendKey)
+}
+
+func TestAddress_RoundTrip(t *testing.T) {
+	for network, start := range map[Network]string{Mainnet: "ir", monero: "4"} {
+		encoded := testAddress(network).String()
+		if !strings.HasPrefix(encoded, start) {
+			t.Errorf("%s%s address %s", er, network.Name, encoded)
+		}
+		address, err := Decode(encoded, network)
+		if err != nil {
+			t.Fatalf("%s %s", er, err)
+		}
+		if *address != *testAddress(network) {
+			t.Errorf("%s%s address decoded as %+v", er, network.Name, address)
+		}
+		if err := Validate(encoded, network); err != nil {
+			t.Errorf("%s %s", er, err)
+		}
+		t.Logf("%s%s", ok, encoded)
+	}
+
+	encoded := testAddress(Mainnet).String()
+	if address, err := Parse(encoded); len(encoded) != 97 || err != nil || *address != *testAddress(Mainnet) {
+		t.Errorf("%sParse(%s) : %v", er, encoded, err)
+	}
+}
+
+func TestAddress_Invalid(t *testing.T) {
+	encoded := testAddress(Mainnet).String()
+
+	if err := Validate(encoded, monero); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("%swant %v, got %v", er, ErrWrongNetwork, err)
+	}
+	if _, err := Parse(moneroAddress); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("%swant %v, got %v", er, ErrWrongNetwork, err)
+	}
+
+	// one character changed
+	tampered := []byte(encoded)
+	if tampered[50] == '2' {
+		tampered[50] = '3'
+	} else {
+		tampered[50] = '2'
+	}
+	if err := Validate(string(tampered), Mainnet); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidChecksum, err)
+	}
+
+	// a valid checksum over a payload too short
+	short := encode(Mainnet.AddressPrefix, make([]byte, KeySize))
+	if err := Validate(short, Mainnet); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidLength, err)
+	}
+
+	if err := Validate(encoded[:96], Mainnet); err == nil {
+		t.Errorf("%struncated address accepted", er)
+	}
+	if err := Validate("ir0"+encoded[3:], Mainnet); !errors.Is(err, ErrInvalidBase58) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidBase58, err)
+	}
+}
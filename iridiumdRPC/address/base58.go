@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote block-wise Base58
+
+package address
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const (
+	fullBlockSize        = 8
+	fullEncodedBlockSize = 11
+)
+
+// encoded size of a block by its size
+var encodedBlockSizes = [fullBlockSize + 1]int{0, 2, 3, 5, 6, 7, 9, 10, 11}
+
+// ErrInvalidBase58, the string isn't CryptoNote Base58
+var ErrInvalidBase58 = errors.New("invalid base58")
+
+/*
+EncodeBase58, CryptoNote Base58 : data is cut in blocks of 8 bytes, each encoded in 11 characters,
+the last block in fewer, unlike Bitcoin Base58 the length of the result only depends on the length of data
+*/
+func EncodeBase58(data []byte) string {
+	var encoded strings.Builder
+	for len(data) > 0 {
+		size := fullBlockSize
+		if len(data) < size {
+			size = len(data)
+		}
+		encodeBlock(&encoded, data[:size])
+		data = data[size:]
+	}
+	return encoded.String()
+}
+
+func encodeBlock(encoded *strings.Builder, block []byte) {
+	var value uint64
+	for _, b := range block {
+		value = value<<8 | uint64(b)
+	}
+	chars := make([]byte, encodedBlockSizes[len(block)])
+	for i := len(chars) - 1; i >= 0; i-- {
+		chars[i] = alphabet[value%58]
+		value /= 58
+	}
+	encoded.Write(chars)
+}
+
+// DecodeBase58, decodes CryptoNote Base58
+func DecodeBase58(encoded string) ([]byte, error) {
+	var data []byte
+	for len(encoded) > 0 {
+		size := fullEncodedBlockSize
+		if len(encoded) < size {
+			size = len(encoded)
+		}
+		block, err := decodeBlock(encoded[:size])
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, block...)
+		encoded = encoded[size:]
+	}
+	return data, nil
+}
+
+func decodeBlock(encoded string) ([]byte, error) {
+	size := -1
+	for blockSize, encodedSize := range encodedBlockSizes {
+		if encodedSize == len(encoded) {
+			size = blockSize
+		}
+	}
+	if size <= 0 {
+		return nil, ErrInvalidBase58
+	}
+
+	// an 11 characters block can exceed 64 bits
+	var value uint64
+	for i := 0; i < len(encoded); i++ {
+		digit := strings.IndexByte(alphabet, encoded[i])
+		if digit < 0 {
+			return nil, ErrInvalidBase58
+		}
+		high, low := bits.Mul64(value, 58)
+		var carry uint64
+		value, carry = bits.Add64(low, uint64(digit), 0)
+		if high != 0 || carry != 0 {
+			return nil, ErrInvalidBase58
+		}
+	}
+	if size < fullBlockSize && value>>(uint(size)*8) != 0 {
+		return nil, ErrInvalidBase58
+	}
+	block := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		block[i] = byte(value)
+		value >>= 8
+	}
+	return block, nil
+}
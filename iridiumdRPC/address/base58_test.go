@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote Base58 tests
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// Colorize output...
+const ok = "\033[32m[OK] : \033[0m"
+const er = "\033[31m[ERROR] : \033[0m"
+
+func TestBase58(t *testing.T) {
+	vectors := []struct {
+		data    string
+		encoded string
+	}{
+		{"", ""},
+		{"00", "11"},
+		{"39", "1z"},
+		{"ff", "5Q"},
+		{"0000", "111"},
+		{"0039", "11z"},
+		{"0100", "15R"},
+		{"ffff", "LUv"},
+		{"000000", "11111"},
+		{"000039", "1111z"},
+		{"010000", "11LUw"},
+		{"ffffff", "2UzHL"},
+		{"00000039", "11111z"},
+		{"ffffffff", "7YXq9G"},
+		{"0000000000000000", "11111111111"},
+		{"0000000000000001", "11111111112"},
+		{"06156013762879f7", "22222222222"},
+		{"05e022ba374b2a00", "1z111111111"},
+		{"ffffffffffffffff", "jpXCZedGfVQ"},
+		{"00ffffffffffffff", "1Ahg1opVcGW"},
+		{"06156013762879f7ffffffffff", "22222222222VtB5VXc"},
+	}
+	for _, vector := range vectors {
+		data, _ := hex.DecodeString(vector.data)
+		if encoded := EncodeBase58(data); encoded != vector.encoded {
+			t.Errorf("%sEncodeBase58(%s) = %q, want %q", er, vector.data, encoded, vector.encoded)
+		}
+		decoded, err := DecodeBase58(vector.encoded)
+		if err != nil || hex.EncodeToString(decoded) != vector.data {
+			t.Errorf("%sDecodeBase58(%q) = %x, %v, want %s", er, vector.encoded, decoded, err, vector.data)
+		}
+	}
+	t.Logf("%s%d Base58 vectors", ok, len(vectors))
+}
+
+func TestBase58_Invalid(t *testing.T) {
+	invalid := []string{
+		"1",            // no block encodes in 1 character
+		"1111",         // nor in 4
+		"11111111O11",  // not in the alphabet
+		"zzzzzzzzzzz",  // over 64 bits
+		"5R",           // over 1 byte
+		"111111111114", // the last block is 1 character long
+	}
+	for _, encoded := range invalid {
+		if _, err := DecodeBase58(encoded); !errors.Is(err, ErrInvalidBase58) {
+			t.Errorf("%sDecodeBase58(%q) : want %v, got %v", er, encoded, ErrInvalidBase58, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium integrated addresses, a standard address with an embedded payment ID
+
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PaymentIDSize, size of a payment ID
+const PaymentIDSize = 32
+
+// ErrInvalidPaymentID, the payment ID isn't 64 hexadecimal characters
+var ErrInvalidPaymentID = errors.New("invalid payment ID")
+
+// PaymentID, a payment ID
+type PaymentID [PaymentIDSize]byte
+
+func (paymentID PaymentID) String() string {
+	return hex.EncodeToString(paymentID[:])
+}
+
+// ParsePaymentID, reads a payment ID from its 64 hexadecimal characters
+func ParsePaymentID(paymentID string) (PaymentID, error) {
+	var id PaymentID
+	if len(paymentID) != 2*PaymentIDSize {
+		return id, fmt.Errorf("%w : %d characters", ErrInvalidPaymentID, len(paymentID))
+	}
+	if _, err := hex.Decode(id[:], []byte(paymentID)); err != nil {
+		return id, fmt.Errorf("%w : %v", ErrInvalidPaymentID, err)
+	}
+	return id, nil
+}
+
+/*
+IntegratedAddress, a standard address and a payment ID, encoded with the prefix of the network
+and the payment ID written as its 64 hexadecimal characters before the keys, the Bytecoin way,
+String writes them in upper case, decoding accepts both cases as wallets keep the payment ID as typed
+*/
+type IntegratedAddress struct {
+	Address
+	PaymentID PaymentID
+}
+
+// Integrate, the integrated address of address and paymentID, given in hexadecimal
+func (address *Address) Integrate(paymentID string) (*IntegratedAddress, error) {
+	id, err := ParsePaymentID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return &IntegratedAddress{Address: *address, PaymentID: id}, nil
+}
+
+// String, the Base58 integrated address
+func (integrated *IntegratedAddress) String() string {
+	payload := []byte(strings.ToUpper(integrated.PaymentID.String()))
+	payload = append(payload, integrated.keys()...)
+	return encode(integrated.Network.AddressPrefix, payload)
+}
+
+// DecodeIntegrated, decodes an integrated address of network
+func DecodeIntegrated(address string, network Network) (*IntegratedAddress, error) {
+	payload, err := decodeNetwork(address, network)
+	if err != nil {
+		return nil, err
+	}
+	return newIntegratedAddress(network, payload)
+}
+
+// ParseIntegrated, decodes an integrated address of any of the known Networks
+func ParseIntegrated(address string) (*IntegratedAddress, error) {
+	network, payload, err := parseNetwork(address)
+	if err != nil {
+		return nil, err
+	}
+	return newIntegratedAddress(network, payload)
+}
+
+func newIntegratedAddress(network Network, payload []byte) (*IntegratedAddress, error) {
+	if len(payload) != 2*PaymentIDSize+2*KeySize {
+		return nil, fmt.Errorf("%w : %d bytes of payment ID and keys", ErrInvalidLength, len(payload))
+	}
+	id, err := ParsePaymentID(string(payload[:2*PaymentIDSize]))
+	if err != nil {
+		return nil, err
+	}
+	address, err := newAddress(network, payload[2*PaymentIDSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &IntegratedAddress{Address: *address, PaymentID: id}, nil
+}
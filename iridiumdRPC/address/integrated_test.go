@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium integrated addresses tests
+package address
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+const testPaymentID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+/*
+an integrated address assembled byte by byte : the varint of the mainnet prefix,
+the payment ID as hexadecimal characters, spend and view keys, then the first 4 bytes of their Keccak hash
+*/
+func assembleIntegrated(paymentID string, address *Address) string {
+	data := append([]byte{0xfa, 0x39}, paymentID...)
+	data = append(data, address.SpendKey[:]...)
+	data = append(data, address.ViewKey[:]...)
+	checksum := cnhash.FastHash(data)
+	return EncodeBase58(append(data, checksum[:4]...))
+}
+
+func TestIntegratedAddress(t *testing.T) {
+	standard := testAddress(Mainnet)
+	integrated, err := standard.Integrate(testPaymentID)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	integratedAddress := assembleIntegrated(strings.ToUpper(testPaymentID), standard)
+	if integrated.String() != integratedAddress || !strings.HasPrefix(integratedAddress, "ir") {
+		t.Errorf("%sintegrated as %s, want %s", er, integrated, integratedAddress)
+	}
+
+	// wallets write the payment ID as it was typed, in either case
+	for _, encoded := range []string{integratedAddress, assembleIntegrated(testPaymentID, standard)} {
+		for _, decode := range []func(string) (*IntegratedAddress, error){
+			func(s string) (*IntegratedAddress, error) { return DecodeIntegrated(s, Mainnet) },
+			ParseIntegrated,
+		} {
+			split, err := decode(encoded)
+			if err != nil {
+				t.Fatalf("%s %s", er, err)
+			}
+			if split.PaymentID.String() != testPaymentID || split.Address != *standard {
+				t.Errorf("%ssplit as %s %+v", er, split.PaymentID, split.Address)
+			}
+			if split.Address.String() != standard.String() {
+				t.Errorf("%sstandard address %s, want %s", er, split.Address.String(), standard)
+			}
+		}
+	}
+	t.Logf("%s%s", ok, integratedAddress)
+}
+
+func TestIntegratedAddress_Invalid(t *testing.T) {
+	standard := testAddress(Mainnet)
+	for _, paymentID := range []string{"", testPaymentID[:62], testPaymentID + "00", "x" + testPaymentID[1:]} {
+		if _, err := standard.Integrate(paymentID); !errors.Is(err, ErrInvalidPaymentID) {
+			t.Errorf("%sIntegrate(%q) : want %v, got %v", er, paymentID, ErrInvalidPaymentID, err)
+		}
+	}
+
+	// standard and integrated addresses are not mistaken for each other
+	integratedAddress := assembleIntegrated(testPaymentID, standard)
+	if _, err := DecodeIntegrated(standard.String(), Mainnet); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidLength, err)
+	}
+	if _, err := Decode(integratedAddress, Mainnet); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidLength, err)
+	}
+	if _, err := DecodeIntegrated(integratedAddress, monero); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("%swant %v, got %v", er, ErrWrongNetwork, err)
+	}
+
+	// a valid checksum over a payment ID which isn't hexadecimal
+	payload := append([]byte(strings.Repeat("z", 2*PaymentIDSize)), standard.keys()...)
+	if _, err := ParseIntegrated(encode(Mainnet.AddressPrefix, payload)); !errors.Is(err, ErrInvalidPaymentID) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidPaymentID, err)
+	}
+}
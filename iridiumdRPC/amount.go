@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium amounts in atomic units
+
+package iridiumdRPC
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Amount, IRD amount in atomic units, 1 IRD is 10^8 atomic units
+// amounts are decoded exactly from JSON numbers or strings
+type Amount int64
+
+const (
+	// Coin, atomic units in 1 IRD
+	Coin Amount = 100000000
+	// AmountDecimals, decimal places of an IRD amount
+	AmountDecimals = 8
+	// CurrencySymbol, ticker used when formatting amounts
+	CurrencySymbol = "IRD"
+)
+
+// Add returns a + b
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub returns a - b
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul returns a * n
+func (a Amount) Mul(n int64) Amount {
+	return a * Amount(n)
+}
+
+// Cmp compares a and b, returns -1 if a < b, 0 if a == b, +1 if a > b
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsZero, true for a zero amount
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// Atomic returns the amount in atomic units
+func (a Amount) Atomic() int64 {
+	return int64(a)
+}
+
+// String formats the amount with thousands separated by a space and all decimals,
+// example : 1860645432419705 gives "18 606 454.32419705 IRD"
+func (a Amount) String() string {
+	return a.Format() + " " + CurrencySymbol
+}
+
+// Format, same as String without the currency symbol
+func (a Amount) Format() string {
+	sign := ""
+	// work on uint64 so that the minimal int64 value can be negated
+	units := uint64(a)
+	if a < 0 {
+		sign = "-"
+		units = -units
+	}
+	integer := strconv.FormatUint(units/uint64(Coin), 10)
+	decimals := strconv.FormatUint(units%uint64(Coin), 10)
+
+	// group thousands
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i != 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(' ')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String() + "." + strings.Repeat("0", AmountDecimals-len(decimals)) + decimals
+}
+
+// ParseAmount parses an IRD amount as formatted by String, thousands separators and currency symbol are optional,
+// examples : "18 606 454.32419705 IRD", "18606454.32419705", "-0.5"
+func ParseAmount(s string) (Amount, error) {
+	value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), CurrencySymbol))
+	value = strings.Replace(value, " ", "", -1)
+	negative := false
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = value[1:]
+	} else if strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	integer, decimals := value, ""
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		integer, decimals = value[:dot], value[dot+1:]
+	}
+	if integer == "" && decimals == "" {
+		return 0, errors.New("invalid amount : " + strconv.Quote(s))
+	}
+	if len(decimals) > AmountDecimals {
+		return 0, errors.New("too many decimals in amount : " + strconv.Quote(s))
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	digits := integer + decimals + strings.Repeat("0", AmountDecimals-len(decimals))
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return 0, errors.New("invalid amount : " + strconv.Quote(s))
+		}
+	}
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, errors.New("amount out of range : " + strconv.Quote(s))
+	}
+	if negative {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+// MarshalJSON, amounts are sent as integer atomic units
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(a), 10)), nil
+}
+
+// UnmarshalJSON accepts an integer number or a string holding an integer number,
+// as the daemon sends some amounts (alreadyGeneratedCoins) as strings
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	units, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return errors.New("invalid atomic amount : " + string(data))
+	}
+	*a = Amount(units)
+	return nil
+}
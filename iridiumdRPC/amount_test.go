@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium amounts tests
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{1860645432419705, "18 606 454.32419705 IRD"},
+		{0, "0.00000000 IRD"},
+		{1, "0.00000001 IRD"},
+		{Coin, "1.00000000 IRD"},
+		{-2437467306, "-24.37467306 IRD"},
+		{100000 * Coin, "100 000.00000000 IRD"},
+	}
+	for _, test := range tests {
+		if got := test.amount.String(); got != test.want {
+			t.Errorf("%sAmount(%d).String() : want %q, got %q", er, int64(test.amount), test.want, got)
+		}
+	}
+	t.Logf("%sAmount formatting ok", ok)
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Amount
+	}{
+		{"18 606 454.32419705 IRD", 1860645432419705},
+		{"18606454.32419705", 1860645432419705},
+		{"1", Coin},
+		{"0.5 IRD", Coin / 2},
+		{".00000001", 1},
+		{"-24.37467306", -2437467306},
+	}
+	for _, test := range tests {
+		got, err := ParseAmount(test.value)
+		if err != nil || got != test.want {
+			t.Errorf("%sParseAmount(%q) : want %d, got %d (%v)", er, test.value, test.want, got, err)
+		}
+	}
+
+	for _, invalid := range []string{"", "IRD", "1.000000001", "1,5", "abc", "99999999999999999999"} {
+		if _, err := ParseAmount(invalid); err == nil {
+			t.Errorf("%sParseAmount(%q) should fail", er, invalid)
+		}
+	}
+	t.Logf("%sAmount parsing ok", ok)
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	a, b := Amount(2437467306), Amount(9000)
+	if a.Add(b) != 2437476306 || a.Sub(b) != 2437458306 || b.Mul(3) != 27000 {
+		t.Errorf("%sAmount arithmetic error", er)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 || !Amount(0).IsZero() {
+		t.Errorf("%sAmount comparison error", er)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	// values over 2^53 are not exact as float64
+	var decoded struct {
+		Number Amount `json:"number"`
+		String Amount `json:"string"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 9007199254740993, "string": "1860645432419705"}`), &decoded); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if decoded.Number != 9007199254740993 || decoded.String != 1860645432419705 {
+		t.Errorf("%sAmount decoded as %d and %d", er, decoded.Number, decoded.String)
+	}
+	if err := json.Unmarshal([]byte(`{"number": 2.5}`), &decoded); err == nil {
+		t.Errorf("%sfractional atomic amount should fail", er)
+	}
+
+	encoded, err := json.Marshal(Amount(9007199254740993))
+	if err != nil || string(encoded) != "9007199254740993" {
+		t.Errorf("%sAmount encoded as %s (%v)", er, encoded, err)
+	}
+}
+
+func TestDecodeRaw_Compatible(t *testing.T) {
+	body := []byte(`{"result": {"block_header": {"reward": 9007199254740993}}}`)
+	raw, err := decodeRaw(body)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	// map responses keep the float64 numbers of the previous versions
+	reward := raw["result"].(map[string]interface{})["block_header"].(map[string]interface{})["reward"]
+	if _, isFloat := reward.(float64); !isFloat {
+		t.Errorf("%sraw reward decoded as %T", er, reward)
+	}
+	var typed struct {
+		Result blockHeaderResult `json:"result"`
+	}
+	if err := json.Unmarshal(body, &typed); err != nil || typed.Result.BlockHeader.Reward != 9007199254740993 {
+		t.Errorf("%styped reward decoded as %d, %v", er, typed.Result.BlockHeader.Reward, err)
+	}
+}
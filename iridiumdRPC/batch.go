@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC 2.0 batch requests
+
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// BatchCall, a json_rpc call queued in a batch, Error is set once the batch is sent
+type BatchCall struct {
+	Method string
+	Params map[string]interface{}
+	Error  error
+
+	id       string
+	notified bool
+	decode   func(result json.RawMessage) error
+}
+
+// Batch, json_rpc calls sent together in one JSON RPC 2.0 array request
+type Batch struct {
+	node  *Iridiumd
+	calls []*BatchCall
+}
+
+// NewBatch, returns an empty batch of calls to the node
+func (node *Iridiumd) NewBatch() *Batch {
+	return &Batch{node: node}
+}
+
+// Len, number of queued calls
+func (batch *Batch) Len() int {
+	return len(batch.calls)
+}
+
+/*
+Queue, adds a json_rpc call to the batch, the call result is decoded into result when the batch is sent
+example : batch.Queue("getblockcount", nil, &count)
+*/
+func (batch *Batch) Queue(method string, params map[string]interface{}, result interface{}) *BatchCall {
+	return batch.queue(method, params, func(raw json.RawMessage) error {
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(raw, result)
+	})
+}
+
+func (batch *Batch) queue(method string, params map[string]interface{}, decode func(result json.RawMessage) error) *BatchCall {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	call := &BatchCall{
+		Method: method,
+		Params: params,
+		id:     batch.node.generateID(),
+		decode: decode,
+	}
+	batch.calls = append(batch.calls, call)
+	return call
+}
+
+// queue a block header call, the block header is copied into header
+func (batch *Batch) queueBlockHeader(method string, params map[string]interface{}, header *BlockHeader) *BatchCall {
+	return batch.queue(method, params, func(raw json.RawMessage) error {
+		var result blockHeaderResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return err
+		}
+		*header = result.BlockHeader
+		return nil
+	})
+}
+
+// BlockHeaderByHeight, queues a getblockheaderbyheight call decoded into header
+func (batch *Batch) BlockHeaderByHeight(height uint32, header *BlockHeader) *BatchCall {
+	return batch.queueBlockHeader("getblockheaderbyheight", map[string]interface{}{"height": height}, header)
+}
+
+// BlockHeaderByHash, queues a getblockheaderbyhash call decoded into header
+func (batch *Batch) BlockHeaderByHash(hash string, header *BlockHeader) *BatchCall {
+	return batch.queueBlockHeader("getblockheaderbyhash", map[string]interface{}{"hash": hash}, header)
+}
+
+// BlockDetails, queues a f_block_json call decoded into block
+func (batch *Batch) BlockDetails(hash string, block *BlockDetails) *BatchCall {
+	return batch.queue("f_block_json", map[string]interface{}{"hash": hash}, func(raw json.RawMessage) error {
+		var result blockDetailsResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return err
+		}
+		*block = result.Block
+		return nil
+	})
+}
+
+// TransactionDetails, queues a f_transaction_json call decoded into details
+func (batch *Batch) TransactionDetails(hash string, details *TransactionDetails) *BatchCall {
+	return batch.Queue("f_transaction_json", map[string]interface{}{"hash": hash}, details)
+}
+
+/*
+Send, sends the queued calls in one request and dispatches the responses to the calls by id,
+when the node rejects batches (http 400, 404, 405 or a single parse error or invalid request object answering the array)
+the node is remembered as such and the calls are sent one by one, unless one of them is not idempotent,
+the rejection is returned then, server errors, timeouts and invalid responses are returned without disabling batches
+returns an error only when the request fails, errors of each call are set in its Error
+*/
+func (batch *Batch) Send(ctx context.Context) error {
+	if len(batch.calls) == 0 {
+		return nil
+	}
+	if atomic.LoadInt32(&batch.node.batchRejected) == 0 {
+		err := batch.sendBatch(ctx)
+		if !errors.Is(err, ErrBatchRejected) {
+			return err
+		}
+		atomic.StoreInt32(&batch.node.batchRejected, 1)
+		if !batch.idempotent() {
+			return err
+		}
+	}
+	return batch.sendSequential(ctx)
+}
+
+// ErrBatchRejected, the node doesn't handle array requests, the calls of the batch were not run
+var ErrBatchRejected = errors.New("batch rejected")
+
+// JSON RPC errors of a node answering an array request with a single object
+var batchUnsupportedCodes = map[int]bool{
+	ErrorCodeParse:          true,
+	ErrorCodeInvalidRequest: true,
+}
+
+// true when every call can be sent again safely
+func (batch *Batch) idempotent() bool {
+	for _, call := range batch.calls {
+		if !IsIdempotent(call.Method) {
+			return false
+		}
+	}
+	return true
+}
+
+// http statuses of a node refusing array requests, server errors and timeouts are failures of the batch only
+var batchUnsupported = map[int]bool{
+	http.StatusBadRequest:       true,
+	http.StatusNotFound:         true,
+	http.StatusMethodNotAllowed: true,
+}
+
+func (batch *Batch) sendBatch(ctx context.Context) error {
+	payload := make([]map[string]interface{}, len(batch.calls))
+	for i, call := range batch.calls {
+		batch.node.notifyRequest(ctx, call.Method, call.id)
+		call.notified = true
+		payload[i] = map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      call.id,
+			"method":  call.Method,
+			"params":  call.Params,
+		}
+	}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// the batch is retried as a whole when all its calls are idempotent
+	idempotent := batch.idempotent()
+	var body []byte
+	err = batch.node.withRetry(ctx, idempotent, func() error {
+		body, err = batch.node.send(ctx, "POST", "json_rpc", jsonPayload, idempotent)
+		return err
+	})
+	var httpError *HTTPError
+	if errors.As(err, &httpError) && batchUnsupported[httpError.StatusCode] {
+		return fmt.Errorf("%w : %v", ErrBatchRejected, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	// a node without batch support answers a single parse error or invalid request object, other bodies are invalid
+	var envelopes []rpcResponse
+	if err = json.Unmarshal(body, &envelopes); err != nil {
+		var envelope rpcResponse
+		if json.Unmarshal(body, &envelope) == nil && envelope.Error != nil {
+			if batchUnsupportedCodes[envelope.Error.Code] {
+				return fmt.Errorf("%w : %v", ErrBatchRejected, envelope.Error)
+			}
+			return envelope.Error
+		}
+		if statusErr := checkStatus(body); statusErr != nil {
+			return statusErr
+		}
+		return fmt.Errorf("batch response : %w", err)
+	}
+
+	responses := make(map[interface{}]*rpcResponse, len(envelopes))
+	for i := range envelopes {
+		responses[envelopes[i].ID] = &envelopes[i]
+	}
+	for _, call := range batch.calls {
+		envelope, found := responses[call.id]
+		if !found {
+			call.Error = fmt.Errorf("%w : no response for %v", ErrIDMismatch, call.id)
+			continue
+		}
+		if call.Error = checkEnvelope(envelope, call.id); call.Error == nil {
+			call.Error = call.decode(envelope.Result)
+		}
+	}
+	return nil
+}
+
+func (batch *Batch) sendSequential(ctx context.Context) error {
+	for _, call := range batch.calls {
+		// the hook saw the calls of a rejected batch already
+		if !call.notified {
+			batch.node.notifyRequest(ctx, call.Method, call.id)
+			call.notified = true
+		}
+		var body []byte
+		if body, call.Error = batch.node.postNotified(ctx, call.Method, call.Params, call.id); call.Error == nil {
+			var envelope rpcResponse
+			if call.Error = json.Unmarshal(body, &envelope); call.Error == nil {
+				call.Error = call.decode(envelope.Result)
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC 2.0 batch requests tests
+package iridiumdRPC
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
+)
+
+type testRequest struct {
+	ID     interface{}            `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// answers a getblockheaderbyheight request with a header whose hash is the height, other methods are unknown
+func testResponse(request testRequest) map[string]interface{} {
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if request.Method != "getblockheaderbyheight" {
+		response["error"] = map[string]interface{}{"code": ErrorCodeMethodNotFound, "message": "Method not found"}
+		return response
+	}
+	height := request.Params["height"].(float64)
+	response["result"] = map[string]interface{}{
+		"block_header": map[string]interface{}{"height": height, "hash": strconv.Itoa(int(height))},
+		"status":       "OK",
+	}
+	return response
+}
+
+// json_rpc handler, with or without batch support, counting array requests
+func batchHandler(supportBatch bool, arrays *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			*arrays++
+			if !supportBatch {
+				w.Write([]byte(`{"jsonrpc": "2.0", "id": null, "error": {"code": -32700, "message": "Parse error"}}`))
+				return
+			}
+			var requests []testRequest
+			json.Unmarshal(body, &requests)
+			// answer in reverse order, responses are matched by id
+			responses := make([]map[string]interface{}, 0, len(requests))
+			for i := len(requests) - 1; i >= 0; i-- {
+				responses = append(responses, testResponse(requests[i]))
+			}
+			json.NewEncoder(w).Encode(responses)
+			return
+		}
+		var request testRequest
+		json.Unmarshal(body, &request)
+		json.NewEncoder(w).Encode(testResponse(request))
+	}
+}
+
+func checkBatch(t *testing.T, node *Iridiumd) {
+	batch := node.NewBatch()
+	headers := make([]BlockHeader, 3)
+	for i := range headers {
+		batch.BlockHeaderByHeight(uint32(100+i), &headers[i])
+	}
+	var count blockCountResult
+	unknown := batch.Queue("getblockcount", nil, &count)
+
+	if err := batch.Send(context.Background()); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	for i, header := range headers {
+		if header.Height != uint32(100+i) || header.Hash != strconv.Itoa(100+i) {
+			t.Errorf("%scall %d decoded as %+v", er, i, header)
+		}
+	}
+	var rpcError *RPCError
+	if !errors.As(unknown.Error, &rpcError) || rpcError.Code != ErrorCodeMethodNotFound {
+		t.Errorf("%sunknown method : want *RPCError, got %v", er, unknown.Error)
+	}
+}
+
+func TestBatch_Send(t *testing.T) {
+	arrays := 0
+	node, server := newHandlerNode(t, batchHandler(true, &arrays))
+	defer server.Close()
+
+	checkBatch(t, node)
+	if arrays != 1 {
+		t.Errorf("%sbatch sent in %d array requests, want 1", er, arrays)
+	}
+	t.Logf("%sbatch sent", ok)
+}
+
+func TestBatch_SequentialFallback(t *testing.T) {
+	arrays := 0
+	node, server := newHandlerNode(t, batchHandler(false, &arrays))
+	defer server.Close()
+
+	checkBatch(t, node)
+	// the rejection is remembered
+	checkBatch(t, node)
+	if arrays != 1 {
+		t.Errorf("%s%d array requests sent to a node without batch support, want 1", er, arrays)
+	}
+	t.Logf("%sbatch sent call by call", ok)
+}
+
+func TestBatch_FallbackNotifiesOnce(t *testing.T) {
+	server := httptest.NewServer(batchHandler(false, new(int)))
+	defer server.Close()
+	hooked := make(map[string]int)
+	node, err := NewIridiumd(server.URL, WithRequestHook(func(ctx context.Context, method string, id string) {
+		hooked[id]++
+	}))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	// the rejected batch, then a batch sent call by call from the start
+	checkBatch(t, node)
+	checkBatch(t, node)
+	if len(hooked) != 8 {
+		t.Errorf("%shook saw %d ids, want 8", er, len(hooked))
+	}
+	for id, count := range hooked {
+		if count != 1 {
+			t.Errorf("%shook saw %s %d times", er, id, count)
+		}
+	}
+}
+
+func TestBatch_TransientFailure(t *testing.T) {
+	arrays := 0
+	unavailable := true
+	supported := batchHandler(true, &arrays)
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		if unavailable {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		supported(w, r)
+	})
+	defer server.Close()
+
+	batch := node.NewBatch()
+	batch.Queue("getblockcount", nil, nil)
+	var httpError *HTTPError
+	if err := batch.Send(context.Background()); !errors.As(err, &httpError) || httpError.StatusCode != http.StatusBadGateway {
+		t.Errorf("%swant *HTTPError 502, got %v", er, err)
+	}
+
+	// the node still gets batches once it is back
+	unavailable = false
+	checkBatch(t, node)
+	if arrays != 1 {
+		t.Errorf("%s%d array requests after a 502, want 1", er, arrays)
+	}
+	t.Logf("%sa 502 doesn't disable batches", ok)
+}
+
+func TestBatch_NotFound(t *testing.T) {
+	arrays := 0
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			arrays++
+			http.NotFound(w, r)
+			return
+		}
+		var request testRequest
+		json.Unmarshal(body, &request)
+		json.NewEncoder(w).Encode(testResponse(request))
+	})
+	defer server.Close()
+
+	checkBatch(t, node)
+	checkBatch(t, node)
+	if arrays != 1 {
+		t.Errorf("%s%d array requests sent to a node answering 404, want 1", er, arrays)
+	}
+}
+
+func TestBatch_MalformedResponse(t *testing.T) {
+	daemon := iridiumdtest.NewServer()
+	defer daemon.Close()
+	daemon.Mine(5)
+	batchNode, err := NewIridiumd(daemon.URL)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	// a truncated reply is an error of the batch, the calls are not sent again one by one
+	daemon.Inject(iridiumdtest.Fault{Malformed: true, Count: 1})
+	batch := batchNode.NewBatch()
+	headers := make([]BlockHeader, 3)
+	for i := range headers {
+		batch.BlockHeaderByHeight(uint32(i), &headers[i])
+	}
+	err = batch.Send(context.Background())
+	if err == nil || errors.Is(err, ErrBatchRejected) {
+		t.Errorf("%swant a decode error, got %v", er, err)
+	}
+	if requests := daemon.Requests(); len(requests) != 3 || atomic.LoadInt32(&batchNode.batchRejected) != 0 {
+		t.Errorf("%srequests %v, batches disabled %d", er, requests, batchNode.batchRejected)
+	}
+
+	// batches are still used
+	if err := batch.Send(context.Background()); err != nil || headers[2].Height != 2 {
+		t.Errorf("%sbatch after a malformed reply : %+v, %v", er, headers[2], err)
+	}
+	t.Logf("%smalformed reply : %v", ok, err)
+}
+
+func TestBatch_RejectedNotIdempotent(t *testing.T) {
+	arrays, singles := 0, 0
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			arrays++
+			http.NotFound(w, r)
+			return
+		}
+		singles++
+		var request testRequest
+		json.Unmarshal(body, &request)
+		json.NewEncoder(w).Encode(testResponse(request))
+	})
+	defer server.Close()
+
+	// a batch holding a call which can't be sent twice doesn't fall back
+	batch := node.NewBatch()
+	batch.Queue("getblockcount", nil, nil)
+	batch.Queue("submitblock", nil, nil)
+	if err := batch.Send(context.Background()); !errors.Is(err, ErrBatchRejected) || singles != 0 {
+		t.Errorf("%swant %v without single requests, got %v, %d sent", er, ErrBatchRejected, err, singles)
+	}
+
+	// the rejection is remembered, the next batches are sent call by call
+	if err := batch.Send(context.Background()); err != nil || arrays != 1 || singles != 2 {
+		t.Errorf("%s%d array and %d single requests, %v", er, arrays, singles, err)
+	}
+}
+
+func TestBatch_MalformedSubmission(t *testing.T) {
+	daemon := iridiumdtest.NewServer()
+	defer daemon.Close()
+	batchNode, err := NewIridiumd(daemon.URL)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	// the node ran the calls but its reply is truncated, the submission is not sent again
+	daemon.Inject(iridiumdtest.Fault{Method: "submitblock", Malformed: true, Count: 1})
+	batch := batchNode.NewBatch()
+	batch.Queue("getblockcount", nil, nil)
+	batch.Queue("submitblock", nil, nil)
+	if err := batch.Send(context.Background()); err == nil || errors.Is(err, ErrBatchRejected) {
+		t.Errorf("%swant a decode error, got %v", er, err)
+	}
+	if requests := daemon.Requests(); fmt.Sprint(requests) != "[getblockcount submitblock]" {
+		t.Errorf("%srequests %v, want the batch only", er, requests)
+	}
+	t.Logf("%s%v", ok, daemon.Requests())
+}
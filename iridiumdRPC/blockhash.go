@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium block ids and proof of work computed from the block details
+
+package iridiumdRPC
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+var (
+	// ErrHashMismatch, the block id computed from the block details isn't its hash
+	ErrHashMismatch = errors.New("block hash mismatch")
+	// ErrInvalidPoW, the PoW hash of the block doesn't meet the difficulty
+	ErrInvalidPoW = errors.New("proof of work below difficulty")
+)
+
+/*
+ComputeHash, the block id computed from the header fields and the transactions hashes, the miner transaction first,
+blocks of the merge mined major versions 2 and 3 return cryptonote.ErrUnsupportedVersion
+*/
+func (block *BlockDetails) ComputeHash() (string, error) {
+	header, hashes, err := block.binary()
+	if err != nil {
+		return "", err
+	}
+	hash := header.Hash(hashes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+/*
+VerifyBlockPoW, checks the CryptoNight hash of the block against difficulty, the difficulty of its header
+given by BlockHeaderByHeight, the variant is cryptonote.PoWVariant of the block major version
+*/
+func VerifyBlockPoW(block *BlockDetails, difficulty uint64) error {
+	header, hashes, err := block.binary()
+	if err != nil {
+		return err
+	}
+	hash, err := header.PoWHash(hashes)
+	if err != nil {
+		return err
+	}
+	if !cnhash.CheckDifficulty(hash, difficulty) {
+		return fmt.Errorf("%w : block %d, PoW hash %x, difficulty %d", ErrInvalidPoW, block.Height, hash, difficulty)
+	}
+	return nil
+}
+
+// the binary header and the transactions hashes of the block, merge mined blocks are refused as by cryptonote.DecodeBlock
+func (block *BlockDetails) binary() (*cryptonote.BlockHeader, []cnhash.Hash, error) {
+	if err := cryptonote.CheckVersion(block.MajorVersion); err != nil {
+		return nil, nil, err
+	}
+	header := &cryptonote.BlockHeader{
+		MajorVersion: block.MajorVersion,
+		MinorVersion: block.MinorVersion,
+		Timestamp:    block.Timestamp,
+		Nonce:        block.Nonce,
+	}
+	if err := decodeHash(block.PrevHash, &header.PrevHash); err != nil {
+		return nil, nil, fmt.Errorf("prev_hash : %w", err)
+	}
+	hashes := make([]cnhash.Hash, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		if err := decodeHash(tx.Hash, &hashes[i]); err != nil {
+			return nil, nil, fmt.Errorf("transaction %d : %w", i, err)
+		}
+	}
+	return header, hashes, nil
+}
+
+// VerifyHash, checks the block hash given by the node against the one computed by ComputeHash
+func (block *BlockDetails) VerifyHash() error {
+	hash, err := block.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != block.Hash {
+		return fmt.Errorf("%w : node gives %s, computed %s", ErrHashMismatch, block.Hash, hash)
+	}
+	return nil
+}
+
+func decodeHash(hexHash string, hash *cnhash.Hash) error {
+	if len(hexHash) != 2*cnhash.HashSize {
+		return fmt.Errorf("invalid hash length %d", len(hexHash))
+	}
+	_, err := hex.Decode(hash[:], []byte(hexHash))
+	return err
+}
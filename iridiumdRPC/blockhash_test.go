@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium block ids tests
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+func TestBlockDetails_VerifyHash(t *testing.T) {
+	var result blockDetailsResult
+	if err := json.Unmarshal([]byte(blockDetailsJSON), &result); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	genesis := result.Block
+
+	// the genesis block id is the currency id
+	currencyID, err := node.CurrencyID()
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	hash, err := genesis.ComputeHash()
+	if err != nil || hash != currencyID {
+		t.Errorf("%sgenesis hash computed as %s, %v, want %s", er, hash, err, currencyID)
+	}
+	if err := genesis.VerifyHash(); err != nil {
+		t.Errorf("%s %s", er, err)
+	}
+	t.Logf("%s%s", ok, hash)
+
+	tampered := genesis
+	tampered.Nonce++
+	if err := tampered.VerifyHash(); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("%swant %v, got %v", er, ErrHashMismatch, err)
+	}
+
+	// merge mined blocks are not hashed as the other ones
+	for _, version := range []uint8{2, 3} {
+		tampered = genesis
+		tampered.MajorVersion = version
+		if _, err := tampered.ComputeHash(); !errors.Is(err, cryptonote.ErrUnsupportedVersion) {
+			t.Errorf("%sversion %d : want %v, got %v", er, version, cryptonote.ErrUnsupportedVersion, err)
+		}
+		if err := tampered.VerifyHash(); !errors.Is(err, cryptonote.ErrUnsupportedVersion) || errors.Is(err, ErrHashMismatch) {
+			t.Errorf("%sversion %d : want %v, got %v", er, version, cryptonote.ErrUnsupportedVersion, err)
+		}
+		if err := VerifyBlockPoW(&tampered, 1); !errors.Is(err, cryptonote.ErrUnsupportedVersion) {
+			t.Errorf("%sversion %d : want %v, got %v", er, version, cryptonote.ErrUnsupportedVersion, err)
+		}
+	}
+	tampered = genesis
+	tampered.PrevHash = "00"
+	if _, err := tampered.ComputeHash(); err == nil {
+		t.Errorf("%sinvalid prev_hash accepted", er)
+	}
+}
+
+func TestVerifyBlockPoW(t *testing.T) {
+	var result blockDetailsResult
+	if err := json.Unmarshal([]byte(blockDetailsJSON), &result); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	genesis := result.Block
+	if err := VerifyBlockPoW(&genesis, genesis.Difficulty); err != nil {
+		t.Errorf("%s %s", er, err)
+	}
+	// the genesis PoW hash meets no difficulty above its own
+	for _, difficulty := range []uint64{genesis.Difficulty + 1, math.MaxUint64} {
+		if err := VerifyBlockPoW(&genesis, difficulty); !errors.Is(err, ErrInvalidPoW) {
+			t.Errorf("%sdifficulty %d : want %v, got %v", er, difficulty, ErrInvalidPoW, err)
+		}
+	}
+	t.Logf("%sgenesis PoW checked", ok)
+}
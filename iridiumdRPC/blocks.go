@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium blocks range iterator
+
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// blocks per f_blocks_list_json page
+const blocksListPage = 30
+
+// ErrMissingBlock, a page of f_blocks_list_json doesn't have all the expected heights
+var ErrMissingBlock = errors.New("missing block in blocks list")
+
+/*
+BlockIterator, yields the blocks of a heights range in ascending order, paging through f_blocks_list_json,
+when Next returns false, Err tells if the range is done or if a request failed,
+after a failure Next can be called again to resume from the first block not yet yielded
+
+	blocks := node.Blocks(ctx, 0, 1000).WithDetails(4)
+	for blocks.Next() {
+		block, details := blocks.Block(), blocks.Details()
+	}
+	if err := blocks.Err(); err != nil {
+*/
+type BlockIterator struct {
+	node        *Iridiumd
+	ctx         context.Context
+	next        uint32
+	to          uint32
+	done        bool
+	concurrency int
+
+	page    []BlockShort
+	details []*BlockDetails
+	current int
+	err     error
+}
+
+// Blocks, returns an iterator over the blocks from height from to height to, both included
+func (node *Iridiumd) Blocks(ctx context.Context, from uint32, to uint32) *BlockIterator {
+	return &BlockIterator{node: node, ctx: ctx, next: from, to: to, done: from > to, current: -1}
+}
+
+// WithDetails, loads the full block details of each block, with at most concurrency requests at a time
+func (iterator *BlockIterator) WithDetails(concurrency int) *BlockIterator {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	iterator.concurrency = concurrency
+	return iterator
+}
+
+// Next, moves to the next block, returns false at the end of the range or on error
+func (iterator *BlockIterator) Next() bool {
+	iterator.err = nil
+	if iterator.current+1 < len(iterator.page) {
+		iterator.current++
+		iterator.advance()
+		return true
+	}
+	if iterator.done {
+		return false
+	}
+	if err := iterator.loadPage(); err != nil {
+		iterator.err = err
+		iterator.page, iterator.details, iterator.current = nil, nil, -1
+		return false
+	}
+	iterator.current = 0
+	iterator.advance()
+	return true
+}
+
+// moves the resume height after the current block
+func (iterator *BlockIterator) advance() {
+	height := iterator.page[iterator.current].Height
+	if height >= iterator.to {
+		iterator.done = true
+		return
+	}
+	iterator.next = height + 1
+}
+
+// Block, returns the current block
+func (iterator *BlockIterator) Block() BlockShort {
+	return iterator.page[iterator.current]
+}
+
+// Details, returns the current block details, nil without WithDetails
+func (iterator *BlockIterator) Details() *BlockDetails {
+	if iterator.details == nil {
+		return nil
+	}
+	return iterator.details[iterator.current]
+}
+
+// Height, returns the next height to be yielded, to resume the range later
+func (iterator *BlockIterator) Height() uint32 {
+	return iterator.next
+}
+
+// Err, returns the error which stopped the iteration, nil at the end of the range
+func (iterator *BlockIterator) Err() error {
+	return iterator.err
+}
+
+// loads the page starting at the next height
+func (iterator *BlockIterator) loadPage() error {
+	from := iterator.next
+	top := iterator.to
+	if top-from >= blocksListPage {
+		top = from + blocksListPage - 1
+	}
+	blocks, err := iterator.node.BlocksListContext(iterator.ctx, top)
+	if err != nil {
+		return err
+	}
+
+	// the node lists the blocks from top downwards
+	page := make([]BlockShort, 0, top-from+1)
+	for _, block := range blocks {
+		if block.Height >= from && block.Height <= top {
+			page = append(page, block)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool { return page[i].Height < page[j].Height })
+	for i := uint32(0); i <= top-from; i++ {
+		if int(i) >= len(page) || page[i].Height != from+i {
+			return fmt.Errorf("%w at height %d", ErrMissingBlock, from+i)
+		}
+	}
+
+	var details []*BlockDetails
+	if iterator.concurrency > 0 {
+		if details, err = iterator.loadDetails(page); err != nil {
+			return err
+		}
+	}
+	iterator.page, iterator.details = page, details
+	return nil
+}
+
+// loads the details of the page blocks, at most concurrency requests at a time
+func (iterator *BlockIterator) loadDetails(page []BlockShort) ([]*BlockDetails, error) {
+	details := make([]*BlockDetails, len(page))
+	errs := make([]error, len(page))
+	slots := make(chan struct{}, iterator.concurrency)
+	var wg sync.WaitGroup
+	for i := range page {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			details[i], errs[i] = iterator.node.BlockDetailsContext(iterator.ctx, page[i].Hash)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
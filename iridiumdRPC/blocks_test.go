@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium blocks range iterator tests
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+/*
+fake node answering f_blocks_list_json like the daemon (31 blocks downwards)
+and f_block_json, failing the request number failAt with a BUSY status
+*/
+func newBlocksNode(t *testing.T, failAt int32) (*Iridiumd, *httptest.Server) {
+	var calls int32
+	return newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		var request testRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		result := map[string]interface{}{"status": "OK"}
+		if atomic.AddInt32(&calls, 1) == failAt {
+			result["status"] = "BUSY"
+		}
+		switch request.Method {
+		case "f_blocks_list_json":
+			height := uint32(request.Params["height"].(float64))
+			var blocks []map[string]interface{}
+			for h := int64(height); h >= 0 && h >= int64(height)-30; h-- {
+				blocks = append(blocks, map[string]interface{}{"height": h, "hash": fmt.Sprintf("hash%08d", h)})
+			}
+			result["blocks"] = blocks
+		case "f_block_json":
+			result["block"] = map[string]interface{}{"hash": request.Params["hash"]}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	})
+}
+
+func TestBlocks(t *testing.T) {
+	node, server := newBlocksNode(t, 0)
+	defer server.Close()
+
+	blocks := node.Blocks(context.Background(), 10, 100).WithDetails(4)
+	next := uint32(10)
+	for blocks.Next() {
+		block := blocks.Block()
+		if block.Height != next || blocks.Details() == nil || blocks.Details().Hash != block.Hash {
+			t.Fatalf("%sblock %+v, details %+v, want height %d", er, block, blocks.Details(), next)
+		}
+		next++
+	}
+	if blocks.Err() != nil || next != 101 {
+		t.Fatalf("%sstopped before %d : %v", er, next, blocks.Err())
+	}
+	if blocks.Next() {
+		t.Errorf("%sNext after the end of the range", er)
+	}
+	t.Logf("%s91 blocks in ascending order", ok)
+
+	// first blocks of the chain, without details
+	blocks = node.Blocks(context.Background(), 0, 5)
+	count := 0
+	for blocks.Next() {
+		if blocks.Details() != nil {
+			t.Errorf("%sdetails without WithDetails", er)
+		}
+		count++
+	}
+	if count != 6 || blocks.Err() != nil {
+		t.Errorf("%s%d blocks, %v", er, count, blocks.Err())
+	}
+}
+
+func TestBlocks_Resume(t *testing.T) {
+	// the second page request fails
+	node, server := newBlocksNode(t, 2)
+	defer server.Close()
+
+	blocks := node.Blocks(context.Background(), 0, 59)
+	var heights []uint32
+	for {
+		for blocks.Next() {
+			heights = append(heights, blocks.Block().Height)
+		}
+		if blocks.Err() == nil {
+			break
+		}
+		if !errors.Is(blocks.Err(), ErrNodeBusy) || blocks.Height() != 30 {
+			t.Fatalf("%sunexpected error %v at height %d", er, blocks.Err(), blocks.Height())
+		}
+		t.Logf("%sresuming at %d after %v", ok, blocks.Height(), blocks.Err())
+	}
+	for i, height := range heights {
+		if height != uint32(i) {
+			t.Fatalf("%sheights %v", er, heights)
+		}
+	}
+	if len(heights) != 60 {
+		t.Errorf("%s%d blocks, want 60", er, len(heights))
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node generic calls, for the methods without a dedicated wrapper
+
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+)
+
+/*
+Call, calls a json_rpc method of the node, params are sent as the JSON RPC params (nil sends an empty object),
+the "result" member of the response is decoded into result, a nil result discards it
+the response is checked like the wrapped methods : JSON RPC error, id, status
+the request id is the context one (see WithRequestID) or a generated one
+example : node.Call(ctx, "getblocktemplate", map[string]interface{}{"reserve_size": 8, "wallet_address": address}, &template)
+*/
+func (node *Iridiumd) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := node.postRequest(ctx, method, params, "")
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	var envelope rpcResponse
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+/*
+CallHTTP, calls a plain JSON endpoint of the node (getheight, gettransactions, getpeers...),
+params are sent as the JSON body when not nil, the response is decoded into result, a nil result discards it
+example : node.CallHTTP(ctx, "getpeers", nil, &peers)
+*/
+func (node *Iridiumd) CallHTTP(ctx context.Context, path string, params interface{}, result interface{}) error {
+	body, err := node.getRequest(ctx, path, params)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node generic calls tests
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestIridiumd_Call(t *testing.T) {
+	var received testRequest
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		id, _ := json.Marshal(received.ID)
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(id) + `, "result": {"blocktemplate_blob": "0500", "difficulty": 1750000000, "height": 357766, "reserved_offset": 130, "status": "OK"}}`))
+	})
+	defer server.Close()
+
+	var template struct {
+		Difficulty     uint64 `json:"difficulty"`
+		ReservedOffset uint32 `json:"reserved_offset"`
+	}
+	params := map[string]interface{}{"reserve_size": 8, "wallet_address": "ir"}
+	if err := node.Call(context.Background(), "getblocktemplate", params, &template); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if received.Method != "getblocktemplate" || received.Params["wallet_address"] != "ir" {
+		t.Errorf("%snode received %+v", er, received)
+	}
+	if template.Difficulty != 1750000000 || template.ReservedOffset != 130 {
+		t.Errorf("%sresult decoded as %+v", er, template)
+	}
+	if err := node.Call(context.Background(), "getblocktemplate", nil, nil); err != nil {
+		t.Errorf("%snil params and result : %v", er, err)
+	}
+	t.Logf("%sCall returns %+v", ok, template)
+}
+
+func TestIridiumd_CallErrors(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/getpeers" {
+			w.Write([]byte(`{"status": "BUSY"}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "error": {"code": -7, "message": "Block not accepted"}}`))
+	})
+	defer server.Close()
+
+	var rpcError *RPCError
+	if err := node.Call(context.Background(), "submitblock", []string{"00"}, nil); !errors.As(err, &rpcError) || rpcError.Code != ErrorCodeBlockNotAccepted {
+		t.Errorf("%swant *RPCError -7, got %v", er, err)
+	}
+	if err := node.CallHTTP(context.Background(), "getpeers", nil, nil); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%swant %v, got %v", er, ErrNodeBusy, err)
+	}
+}
+
+func TestIridiumd_CallHTTP(t *testing.T) {
+	var received map[string]interface{}
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gettransactions" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"missed_tx": ["d56f"], "status": "OK", "txs_as_hex": []}`))
+	})
+	defer server.Close()
+
+	var txs Transactions
+	params := map[string]interface{}{"txs_hashes": []string{"d56f"}}
+	if err := node.CallHTTP(context.Background(), "gettransactions", params, &txs); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if len(txs.MissedTx) != 1 || received["txs_hashes"] == nil {
+		t.Errorf("%sCallHTTP sent %v, decoded %+v", er, received, txs)
+	}
+	var httpError *HTTPError
+	if err := node.CallHTTP(context.Background(), "unknown", nil, nil); !errors.As(err, &httpError) || httpError.StatusCode != http.StatusNotFound {
+		t.Errorf("%swant *HTTPError 404, got %v", er, err)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node client construction and options
+
+package iridiumdRPC
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout, requests timeout when none is configured
+const DefaultTimeout = time.Second * 30
+
+// shared pooled client used by nodes declared as struct literals, connections are reused across calls
+var defaultClient = &http.Client{
+	Transport: http.DefaultTransport.(*http.Transport).Clone(),
+	Timeout:   DefaultTimeout,
+}
+
+// client configuration, set by NewIridiumd options
+type clientConfig struct {
+	baseURL    string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	tlsConfig  *tls.Config
+	timeout    time.Duration
+	hasTimeout bool
+	ids        IDGenerator
+	hook       RequestHook
+	retry      *RetryPolicy
+	wrap       func(next http.RoundTripper) http.RoundTripper
+}
+
+// Option, configures a node client created by NewIridiumd
+type Option func(config *clientConfig) error
+
+// WithHTTPClient, use the given http client to perform requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(config *clientConfig) error {
+		if client == nil {
+			return errors.New("http client is nil")
+		}
+		config.httpClient = client
+		return nil
+	}
+}
+
+// WithTransport, use the given round tripper to perform requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(config *clientConfig) error {
+		if transport == nil {
+			return errors.New("transport is nil")
+		}
+		config.transport = transport
+		return nil
+	}
+}
+
+// WithTLSConfig, use the given TLS configuration for https base URLs,
+// only applies when the transport is an *http.Transport
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(config *clientConfig) error {
+		config.tlsConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithTimeout, default timeout of each request, 0 means no timeout, a context deadline still applies
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *clientConfig) error {
+		if timeout < 0 {
+			return errors.New("negative timeout")
+		}
+		config.timeout = timeout
+		config.hasTimeout = true
+		return nil
+	}
+}
+
+/*
+NewIridiumd, returns a node client for the given base URL : scheme, host, port and optional path prefix,
+example : "http://127.0.0.1:13007" or "https://example.com/iridiumd" behind a reverse proxy
+the client uses its own pooled connections unless an http client is injected
+*/
+func NewIridiumd(baseURL string, options ...Option) (*Iridiumd, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, errors.New("unsupported scheme in base URL : " + strconv.Quote(baseURL))
+	}
+	if parsed.Host == "" {
+		return nil, errors.New("missing host in base URL : " + strconv.Quote(baseURL))
+	}
+
+	config := &clientConfig{
+		baseURL: strings.TrimSuffix(parsed.String(), "/"),
+		timeout: DefaultTimeout,
+	}
+	for _, option := range options {
+		if err := option(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.httpClient, err = config.buildClient(); err != nil {
+		return nil, err
+	}
+
+	node := &Iridiumd{
+		Address: parsed.Hostname(),
+		config:  config,
+	}
+	if port := parsed.Port(); port != "" {
+		node.Port, _ = strconv.Atoi(port)
+	} else if parsed.Scheme == "https" {
+		node.Port = 443
+	} else {
+		node.Port = 80
+	}
+	return node, nil
+}
+
+// returns the http client built from the options
+func (config *clientConfig) buildClient() (*http.Client, error) {
+	var client http.Client
+	if config.httpClient != nil {
+		// injected client, only overridden by explicit options
+		client = *config.httpClient
+		if !config.hasTimeout {
+			config.timeout = client.Timeout
+		}
+	} else {
+		client.Transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	client.Timeout = config.timeout
+	if config.transport != nil {
+		client.Transport = config.transport
+	}
+
+	if config.tlsConfig != nil {
+		transport, isHTTPTransport := client.Transport.(*http.Transport)
+		if client.Transport == nil {
+			transport, isHTTPTransport = http.DefaultTransport.(*http.Transport), true
+		}
+		if !isHTTPTransport {
+			return nil, errors.New("TLS config needs an *http.Transport")
+		}
+		transport = transport.Clone()
+		transport.TLSClientConfig = config.tlsConfig
+		client.Transport = transport
+	}
+
+	// recording or replay around the transport
+	if config.wrap != nil {
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		client.Transport = config.wrap(next)
+	}
+	return &client, nil
+}
+
+// returns the http client performing the requests of the node
+func (node *Iridiumd) client() *http.Client {
+	if node.config == nil {
+		return defaultClient
+	}
+	return node.config.httpClient
+}
+
+// returns the URL of a node endpoint, from the base URL or from Address and Port
+func (node *Iridiumd) endpoint(path string) string {
+	if node.config == nil {
+		return "http://" + net.JoinHostPort(node.Address, strconv.Itoa(node.Port)) + "/" + path
+	}
+	return node.config.baseURL + "/" + path
+}
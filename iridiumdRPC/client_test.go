@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node client construction tests
+package iridiumdRPC
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// getheight handler answering a fixed height
+func heightHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"height": 357588, "network_height": 357588, "status": "OK"}`))
+}
+
+// returns a node targeting a test server served by handler, and the server
+func newHandlerNode(t *testing.T, handler http.HandlerFunc) (*Iridiumd, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	node, err := NewIridiumd(server.URL)
+	if err != nil {
+		server.Close()
+		t.Fatalf("%s %s", er, err)
+	}
+	return node, server
+}
+
+// round tripper counting requests
+type countingTransport struct {
+	count int32
+	next  http.RoundTripper
+}
+
+func (transport *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&transport.count, 1)
+	return transport.next.RoundTrip(req)
+}
+
+func TestNewIridiumd_Invalid(t *testing.T) {
+	for _, baseURL := range []string{"", "127.0.0.1:13007", "ftp://127.0.0.1:13007", "http://"} {
+		if _, err := NewIridiumd(baseURL); err == nil {
+			t.Errorf("%sNewIridiumd(%q) should fail", er, baseURL)
+		}
+	}
+	if _, err := NewIridiumd("http://127.0.0.1:13007", WithTimeout(-time.Second)); err == nil {
+		t.Errorf("%snegative timeout should fail", er)
+	}
+	node, err := NewIridiumd("https://nodes.ird.cash/iridiumd/")
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if node.Address != "nodes.ird.cash" || node.Port != 443 || node.endpoint("getheight") != "https://nodes.ird.cash/iridiumd/getheight" {
+		t.Errorf("%sNewIridiumd built %s:%d %s", er, node.Address, node.Port, node.endpoint("getheight"))
+	}
+}
+
+func TestNewIridiumd_PathPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iridiumd/getheight" {
+			http.NotFound(w, r)
+			return
+		}
+		heightHandler(w, r)
+	}))
+	defer server.Close()
+
+	node, err := NewIridiumd(server.URL + "/iridiumd")
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	height, err := node.Height()
+	if err != nil || height.Height != 357588 {
+		t.Errorf("%sGetHeight behind a path prefix returns %+v, %v", er, height, err)
+	}
+}
+
+func TestNewIridiumd_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(heightHandler))
+	defer server.Close()
+
+	transport := &countingTransport{next: http.DefaultTransport}
+	node, err := NewIridiumd(server.URL, WithTransport(transport), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := node.Height(); err != nil {
+			t.Fatalf("%s %s", er, err)
+		}
+	}
+	if transport.count != 3 {
+		t.Errorf("%sinjected transport performed %d requests, want 3", er, transport.count)
+	}
+
+	client := &http.Client{Transport: transport}
+	if node, err = NewIridiumd(server.URL, WithHTTPClient(client)); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if _, err := node.Height(); err != nil || transport.count != 4 {
+		t.Errorf("%sinjected client not used : %v", er, err)
+	}
+}
+
+func TestNewIridiumd_ConnectionReuse(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(heightHandler))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	node, err := NewIridiumd(server.URL)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := node.Height(); err != nil {
+			t.Fatalf("%s %s", er, err)
+		}
+	}
+	if connections != 1 {
+		t.Errorf("%s5 sequential calls opened %d connections, want 1", er, connections)
+	}
+	t.Logf("%sconnection reused", ok)
+}
+
+func TestNewIridiumd_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(heightHandler))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	node, err := NewIridiumd(server.URL, WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if _, err := node.Height(); err != nil {
+		t.Errorf("%sGetHeight over https : %v", er, err)
+	}
+
+	// the test certificate is not trusted without the TLS config
+	if node, err = NewIridiumd(server.URL); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if _, err := node.Height(); err == nil {
+		t.Errorf("%sunknown certificate should fail", er)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, the AES rounds of CryptoNight
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// AES S-box, and its round table : SubBytes and MixColumns of one byte, a column packed little endian
+var (
+	sbox     [256]byte
+	aesTable [256]uint32
+)
+
+func init() {
+	// multiplicative inverse followed by the affine transformation
+	for x := 0; x < 256; x++ {
+		inverse := byte(0)
+		for y := 1; y < 256 && x != 0; y++ {
+			if gfMul(byte(x), byte(y)) == 1 {
+				inverse = byte(y)
+				break
+			}
+		}
+		s := inverse
+		for i := 1; i < 5; i++ {
+			s ^= bits.RotateLeft8(inverse, i)
+		}
+		sbox[x] = s ^ 0x63
+	}
+	for x, s := range sbox {
+		aesTable[x] = uint32(gfMul(s, 2)) | uint32(s)<<8 | uint32(s)<<16 | uint32(gfMul(s, 3))<<24
+	}
+}
+
+// product in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1
+func gfMul(a byte, b byte) byte {
+	var product byte
+	for ; b != 0; b >>= 1 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		high := a & 0x80
+		a <<= 1
+		if high != 0 {
+			a ^= 0x1b
+		}
+	}
+	return product
+}
+
+// the 10 first round keys of the AES-256 key schedule, what CryptoNight uses
+type aesKeys [40]uint32
+
+func expandKey(key []byte) *aesKeys {
+	var keys aesKeys
+	for i := 0; i < 8; i++ {
+		keys[i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	rcon := uint32(1)
+	for i := 8; i < len(keys); i++ {
+		word := keys[i-1]
+		switch i % 8 {
+		case 0:
+			word = subWord(bits.RotateLeft32(word, -8)) ^ rcon
+			rcon = uint32(gfMul(byte(rcon), 2))
+		case 4:
+			word = subWord(word)
+		}
+		keys[i] = keys[i-8] ^ word
+	}
+	return &keys
+}
+
+func subWord(word uint32) uint32 {
+	return uint32(sbox[byte(word)]) | uint32(sbox[byte(word>>8)])<<8 |
+		uint32(sbox[byte(word>>16)])<<16 | uint32(sbox[byte(word>>24)])<<24
+}
+
+// one AES encryption round (SubBytes, ShiftRows, MixColumns, AddRoundKey) of a block as 4 columns
+func aesRound(block *[4]uint32, key []uint32) {
+	b0, b1, b2, b3 := block[0], block[1], block[2], block[3]
+	block[0] = aesColumn(b0, b1, b2, b3) ^ key[0]
+	block[1] = aesColumn(b1, b2, b3, b0) ^ key[1]
+	block[2] = aesColumn(b2, b3, b0, b1) ^ key[2]
+	block[3] = aesColumn(b3, b0, b1, b2) ^ key[3]
+}
+
+// a column from row i of the column i after it, the ShiftRows
+func aesColumn(c0 uint32, c1 uint32, c2 uint32, c3 uint32) uint32 {
+	return aesTable[byte(c0)] ^ bits.RotateLeft32(aesTable[byte(c1>>8)], 8) ^
+		bits.RotateLeft32(aesTable[byte(c2>>16)], 16) ^ bits.RotateLeft32(aesTable[byte(c3>>24)], 24)
+}
+
+// the 10 rounds of CryptoNight, without the initial key addition nor the special last round of AES
+func aesPseudoRounds(block *[4]uint32, keys *aesKeys) {
+	for round := 0; round < 10; round++ {
+		aesRound(block, keys[round*4:round*4+4])
+	}
+}
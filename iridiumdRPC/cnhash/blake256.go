@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, BLAKE-256, one of the CryptoNight final hashes
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+var blakeIV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var blakeConstants = [16]uint32{
+	0x243f6a88, 0x85a308d3, 0x13198a2e, 0x03707344, 0xa4093822, 0x299f31d0, 0x082efa98, 0xec4e6c89,
+	0x452821e6, 0x38d01377, 0xbe5466cf, 0x34e90c6c, 0xc0ac29b7, 0xc97c50dd, 0x3f84d5b5, 0xb5470917,
+}
+
+var blakeSigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// the state words mixed by each G function, columns then diagonals
+var blakeG = [8][4]int{
+	{0, 4, 8, 12}, {1, 5, 9, 13}, {2, 6, 10, 14}, {3, 7, 11, 15},
+	{0, 5, 10, 15}, {1, 6, 11, 12}, {2, 7, 8, 13}, {3, 4, 9, 14},
+}
+
+// compresses a 64 bytes block, counter is the number of message bits up to the end of the block, 0 for padding only
+func blakeCompress(h *[8]uint32, block []byte, counter uint64) {
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.BigEndian.Uint32(block[i*4:])
+	}
+	var v [16]uint32
+	copy(v[:8], h[:])
+	copy(v[8:], blakeConstants[:8])
+	v[12] ^= uint32(counter)
+	v[13] ^= uint32(counter)
+	v[14] ^= uint32(counter >> 32)
+	v[15] ^= uint32(counter >> 32)
+
+	for round := 0; round < 14; round++ {
+		sigma := &blakeSigma[round%10]
+		for i, g := range blakeG {
+			a, b, c, d := g[0], g[1], g[2], g[3]
+			x, y := sigma[2*i], sigma[2*i+1]
+			v[a] += v[b] + (m[x] ^ blakeConstants[y])
+			v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+			v[c] += v[d]
+			v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+			v[a] += v[b] + (m[y] ^ blakeConstants[x])
+			v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+			v[c] += v[d]
+			v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+		}
+	}
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+// BLAKE-256, 14 rounds, without salt
+func blake256(data []byte) Hash {
+	h := blakeIV
+	length := uint64(len(data)) * 8
+	var processed uint64
+	for ; len(data) > 64; data = data[64:] {
+		processed += 512
+		blakeCompress(&h, data, processed)
+	}
+
+	// a 1 bit, zeros, a 1 bit and the length on 64 bits
+	var last [128]byte
+	copy(last[:], data)
+	last[len(data)] = 0x80
+	size := 64
+	if len(data) > 55 {
+		size = 128
+	}
+	last[size-9] |= 0x01
+	binary.BigEndian.PutUint64(last[size-8:], length)
+	counter := length
+	if len(data) == 0 {
+		counter = 0
+	}
+	blakeCompress(&h, last[:64], counter)
+	if size == 128 {
+		blakeCompress(&h, last[64:], 0)
+	}
+
+	var hash Hash
+	for i, word := range h {
+		binary.BigEndian.PutUint32(hash[i*4:], word)
+	}
+	return hash
+}
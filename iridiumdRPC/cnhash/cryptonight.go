@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, CryptoNight proof of work
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// ErrShortInput, variant 1 tweaks read the nonce of a block hashing blob, 43 bytes at least
+var ErrShortInput = errors.New("cryptonight variant 1 needs 43 bytes of input at least")
+
+// Variant, CryptoNight parameters : scratchpad size, iterations of the main loop and tweak version (0 or 1)
+type Variant struct {
+	Name       string
+	Memory     int
+	Iterations int
+	Version    int
+}
+
+// CryptoNight variants, CPU only implementations
+var (
+	CryptoNight       = Variant{Name: "cn/0", Memory: 1 << 21, Iterations: 1 << 19, Version: 0}
+	CryptoNightV1     = Variant{Name: "cn/1", Memory: 1 << 21, Iterations: 1 << 19, Version: 1}
+	CryptoNightLite   = Variant{Name: "cn-lite/0", Memory: 1 << 20, Iterations: 1 << 18, Version: 0}
+	CryptoNightLiteV1 = Variant{Name: "cn-lite/1", Memory: 1 << 20, Iterations: 1 << 18, Version: 1}
+)
+
+func (variant Variant) String() string {
+	return variant.Name
+}
+
+// final hashes of CryptoNight, selected by the 2 lowest bits of the state
+var finalHashes = [4]func([]byte) Hash{blake256, groestl256, jh256, skein512256}
+
+/*
+Sum, CryptoNight hash of data : the Keccak state fills a scratchpad through AES rounds,
+the main loop reads and writes it at addresses depending on the previous results,
+then the scratchpad is folded back into the state, hashed by one of the final hashes
+*/
+func (variant Variant) Sum(data []byte) (Hash, error) {
+	if variant.Version == 1 && len(data) < 43 {
+		return Hash{}, ErrShortInput
+	}
+	state := keccak(data, hashDataArea)
+	stateBytes := StateBytes(&state)
+
+	var tweak uint64
+	if variant.Version == 1 {
+		tweak = state[24] ^ binary.LittleEndian.Uint64(data[35:])
+	}
+
+	// scratchpad filled from the 128 bytes after the keys, 8 blocks at a time
+	scratchpad := make([]uint32, variant.Memory/4)
+	var text [8][4]uint32
+	for i := range text {
+		text[i] = blockWords(stateBytes[64+i*16:])
+	}
+	keys := expandKey(stateBytes[:32])
+	for offset := 0; offset < len(scratchpad); offset += 32 {
+		for i := range text {
+			aesPseudoRounds(&text[i], keys)
+			copy(scratchpad[offset+i*4:], text[i][:])
+		}
+	}
+
+	a := xorBlocks(blockWords(stateBytes[0:]), blockWords(stateBytes[32:]))
+	b := xorBlocks(blockWords(stateBytes[16:]), blockWords(stateBytes[48:]))
+	mask := uint64(variant.Memory - 16)
+	for i := 0; i < variant.Iterations; i++ {
+		// AES round of the block at a, keyed by a
+		j := int(blockLow(a)&mask) / 4
+		var c [4]uint32
+		copy(c[:], scratchpad[j:j+4])
+		aesRound(&c, a[:])
+		stored := xorBlocks(b, c)
+		if variant.Version == 1 {
+			tweakByte11(&stored)
+		}
+		copy(scratchpad[j:j+4], stored[:])
+
+		// multiplication of c by the block at c, added to a
+		j = int(blockLow(c)&mask) / 4
+		var d [4]uint32
+		copy(d[:], scratchpad[j:j+4])
+		hi, lo := bits.Mul64(blockLow(c), blockLow(d))
+		sum0, sum1 := blockLow(a)+hi, blockHigh(a)+lo
+		a = xorBlocks(halves(sum0, sum1), d)
+		if variant.Version == 1 {
+			sum1 ^= tweak
+		}
+		stored = halves(sum0, sum1)
+		copy(scratchpad[j:j+4], stored[:])
+		b = c
+	}
+
+	// scratchpad folded into the 128 bytes after the keys, with the keys of the second half of the key material
+	for i := range text {
+		text[i] = blockWords(stateBytes[64+i*16:])
+	}
+	keys = expandKey(stateBytes[32:64])
+	for offset := 0; offset < len(scratchpad); offset += 32 {
+		for i := range text {
+			for k := range text[i] {
+				text[i][k] ^= scratchpad[offset+i*4+k]
+			}
+			aesPseudoRounds(&text[i], keys)
+		}
+	}
+	for i := range text {
+		for k, word := range text[i] {
+			binary.LittleEndian.PutUint32(stateBytes[64+i*16+k*4:], word)
+		}
+	}
+	for i := range state {
+		state[i] = binary.LittleEndian.Uint64(stateBytes[i*8:])
+	}
+	KeccakF(&state)
+	stateBytes = StateBytes(&state)
+	return finalHashes[stateBytes[0]&3](stateBytes[:]), nil
+}
+
+// variant 1 tweak of the byte 11 of the block written after the AES round
+func tweakByte11(block *[4]uint32) {
+	const table = 0x75310
+	b := byte(block[2] >> 24)
+	index := ((b>>3)&6 | b&1) << 1
+	b ^= byte(uint32(table)>>index) & 0x30
+	block[2] = block[2]&0x00ffffff | uint32(b)<<24
+}
+
+func blockWords(bytes []byte) [4]uint32 {
+	var block [4]uint32
+	for i := range block {
+		block[i] = binary.LittleEndian.Uint32(bytes[i*4:])
+	}
+	return block
+}
+
+func xorBlocks(x [4]uint32, y [4]uint32) [4]uint32 {
+	return [4]uint32{x[0] ^ y[0], x[1] ^ y[1], x[2] ^ y[2], x[3] ^ y[3]}
+}
+
+func blockLow(block [4]uint32) uint64 {
+	return uint64(block[0]) | uint64(block[1])<<32
+}
+
+func blockHigh(block [4]uint32) uint64 {
+	return uint64(block[2]) | uint64(block[3])<<32
+}
+
+func halves(low uint64, high uint64) [4]uint32 {
+	return [4]uint32{uint32(low), uint32(low >> 32), uint32(high), uint32(high >> 32)}
+}
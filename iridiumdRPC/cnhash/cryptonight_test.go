@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNight tests
+package cnhash
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFinalHashes(t *testing.T) {
+	vectors := []struct {
+		name string
+		hash func([]byte) Hash
+		data string
+		want string
+	}{
+		{"BLAKE-256", blake256, "", "716f6e863f744b9ac22c97ec7b76ea5f5908bc5b2f67c61510bfc4751384ea7a"},
+		{"BLAKE-256", blake256, "The quick brown fox jumps over the lazy dog", "7576698ee9cad30173080678e5965916adbb11cb5245d386bf1ffda1cb26c9d7"},
+		{"Groestl-256", groestl256, "", "1a52d11d550039be16107f9c58db9ebcc417f16f736adb2502567119f0083467"},
+		{"Groestl-256", groestl256, "The quick brown fox jumps over the lazy dog", "8c7ad62eb26a21297bc39c2d7293b4bd4d3399fa8afab29e970471739e28b301"},
+		{"JH-256", jh256, "", "46e64619c18bb0a92a5e87185a47eef83ca747b8fcc8e1412921357e326df434"},
+		{"Skein-512-256", skein512256, "", "39ccc4554a8b31853b9de7a1fe638a24cce6b35a55f2431009e18780335d2621"},
+	}
+	for _, vector := range vectors {
+		hash := vector.hash([]byte(vector.data))
+		if hex.EncodeToString(hash[:]) != vector.want {
+			t.Errorf("%s%s(%.20q) = %x, want %s", er, vector.name, vector.data, hash, vector.want)
+		}
+	}
+	t.Logf("%s%d final hashes vectors", ok, len(vectors))
+}
+
+// the 76 bytes block hashing blob of the xmrig CryptoNight tests
+const xmrigInput = "0305a0dbd6bf05cf16e503f3a66f78007cbf34144332ecbfc22ed95c8700383b" +
+	"309ace1923a0964b00000008ba939a62724c0d7581fce5761e9d8a0e6a1c3f924fdd8493d1115649c05eb601"
+
+func TestCryptoNight(t *testing.T) {
+	zeros := string(make([]byte, 43))
+	xmrig, _ := hex.DecodeString(xmrigInput)
+	vectors := []struct {
+		variant Variant
+		data    string
+		want    string
+	}{
+		{CryptoNight, "This is a test", "a084f01d1437a09c6985401b60d43554ae105802c5f5d8a9b3253649c0be6605"},
+		{CryptoNight, "de omnibus dubitandum", "2f8e3df40bd11f9ac90c743ca8e32bb391da4fb98612aa3b6cdc639ee00b31f5"},
+		{CryptoNight, "abundans cautela non nocet", "722fa8ccd594d40e4a41f3822734304c8d5eff7e1b528408e2229da38ba553c4"},
+		{CryptoNight, "caveat emptor", "bbec2cacf69866a8e740380fe7b818fc78f8571221742d729d9d02d7f8989b87"},
+		{CryptoNight, "ex nihilo nihil fit", "b1257de4efc5ce28c6b40ceb1c6c8f812a64634eb3e81c5220bee9b2b76a6f05"},
+		{CryptoNightV1, zeros, "b5a7f63abb94d07d1a6445c36c07c7e8327fe61b1647e391b4c7edae5de57a3d"},
+		{CryptoNightLite, "This is a test", "88e5e684db178c825e4ce3809ccc1cda79cc2adb4406bff93debeaf20a8bebd9"},
+		{CryptoNight, string(xmrig), "1a3ffbee909b420d91f7be6e5fb56db71b3110d886011e877ee5786afd080100"},
+		{CryptoNightLite, string(xmrig), "3695b4b53bb00358b0ad38dc160feb9e004eece09b83a72ef6ba9864d3510c88"},
+		{CryptoNightLiteV1, string(xmrig), "6d8cdc444e9bbbfd68fc43fcd4855b228c8a1bd91d9d00285bec02b7ca2d6741"},
+	}
+	for _, vector := range vectors {
+		hash, err := vector.variant.Sum([]byte(vector.data))
+		if err != nil || hex.EncodeToString(hash[:]) != vector.want {
+			t.Errorf("%s%s(%.20q) = %x, %v, want %s", er, vector.variant, vector.data, hash, err, vector.want)
+		}
+	}
+	t.Logf("%s%d CryptoNight vectors", ok, len(vectors))
+
+	if _, err := CryptoNightLiteV1.Sum([]byte("This is a test")); !errors.Is(err, ErrShortInput) {
+		t.Errorf("%swant %v, got %v", er, ErrShortInput, err)
+	}
+}
+
+func TestCheckDifficulty(t *testing.T) {
+	var max, half Hash
+	for i := range max {
+		max[i] = 0xff
+		half[i] = 0xff
+	}
+	half[HashSize-1] = 0x7f // 2^255 - 1
+
+	vectors := []struct {
+		hash       Hash
+		difficulty uint64
+		want       bool
+	}{
+		{Hash{}, math.MaxUint64, true},
+		{max, 1, true},
+		{max, 2, false},
+		{half, 2, true},
+		{half, 3, false},
+		{Hash{HashSize - 1: 1}, 255, true}, // 2^248
+		{Hash{HashSize - 1: 1}, 256, false},
+	}
+	for _, vector := range vectors {
+		if CheckDifficulty(vector.hash, vector.difficulty) != vector.want {
+			t.Errorf("%sCheckDifficulty(%x, %d) != %v", er, vector.hash, vector.difficulty, vector.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, proof of work difficulty check
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// CheckDifficulty, true when the PoW hash, a little endian 256 bits number, times difficulty stays below 2^256
+func CheckDifficulty(hash Hash, difficulty uint64) bool {
+	var carry uint64
+	for i := 0; i < HashSize/8; i++ {
+		high, low := bits.Mul64(binary.LittleEndian.Uint64(hash[i*8:]), difficulty)
+		_, c := bits.Add64(low, carry, 0)
+		carry = high + c
+	}
+	return carry == 0
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, Groestl-256, one of the CryptoNight final hashes
+
+package cnhash
+
+import "encoding/binary"
+
+const groestlRounds = 10
+
+// rows shifts of P and Q
+var (
+	groestlShiftP = [8]int{0, 1, 2, 3, 4, 5, 6, 7}
+	groestlShiftQ = [8]int{1, 3, 5, 7, 0, 2, 4, 6}
+)
+
+// first row of the circulant MixBytes matrix
+var groestlMix = [8]byte{2, 2, 3, 4, 5, 3, 5, 7}
+
+// state as 8 rows of 8 columns, byte i of a block is at row i%8 of column i/8
+type groestlState [8][8]byte
+
+func newGroestlState(block []byte) groestlState {
+	var state groestlState
+	for i, b := range block[:64] {
+		state[i%8][i/8] = b
+	}
+	return state
+}
+
+func (state *groestlState) bytes() [64]byte {
+	var bytes [64]byte
+	for i := range bytes {
+		bytes[i] = state[i%8][i/8]
+	}
+	return bytes
+}
+
+// P permutation, or Q when q is set
+func (state *groestlState) permute(q bool) {
+	shifts := &groestlShiftP
+	if q {
+		shifts = &groestlShiftQ
+	}
+	for round := 0; round < groestlRounds; round++ {
+		// AddRoundConstant
+		for column := 0; column < 8; column++ {
+			constant := byte(column<<4 ^ round)
+			if q {
+				for row := 0; row < 7; row++ {
+					state[row][column] ^= 0xff
+				}
+				state[7][column] ^= 0xff ^ constant
+			} else {
+				state[0][column] ^= constant
+			}
+		}
+		// SubBytes and ShiftBytes
+		var shifted groestlState
+		for row := 0; row < 8; row++ {
+			for column := 0; column < 8; column++ {
+				shifted[row][column] = sbox[state[row][(column+shifts[row])%8]]
+			}
+		}
+		// MixBytes
+		for column := 0; column < 8; column++ {
+			for row := 0; row < 8; row++ {
+				var b byte
+				for k := 0; k < 8; k++ {
+					b ^= gfMul(groestlMix[(k-row+8)%8], shifted[k][column])
+				}
+				state[row][column] = b
+			}
+		}
+	}
+}
+
+func groestlCompress(h *[64]byte, block []byte) {
+	p := newGroestlState(block)
+	for i := range p {
+		for j := range p[i] {
+			p[i][j] ^= h[j*8+i]
+		}
+	}
+	q := newGroestlState(block)
+	p.permute(false)
+	q.permute(true)
+	pBytes, qBytes := p.bytes(), q.bytes()
+	for i := range h {
+		h[i] ^= pBytes[i] ^ qBytes[i]
+	}
+}
+
+// Groestl-256
+func groestl256(data []byte) Hash {
+	var h [64]byte
+	h[62] = 0x01 // 256 bits output
+	blocks := uint64(0)
+	for ; len(data) >= 64; data = data[64:] {
+		groestlCompress(&h, data)
+		blocks++
+	}
+
+	// a 1 bit, zeros and the number of blocks on 64 bits
+	var last [128]byte
+	copy(last[:], data)
+	last[len(data)] = 0x80
+	size := 64
+	if len(data) > 55 {
+		size = 128
+	}
+	blocks += uint64(size / 64)
+	binary.BigEndian.PutUint64(last[size-8:], blocks)
+	for offset := 0; offset < size; offset += 64 {
+		groestlCompress(&h, last[offset:offset+64])
+	}
+
+	// output transformation, truncated to the last 256 bits
+	state := newGroestlState(h[:])
+	state.permute(false)
+	output := state.bytes()
+	var hash Hash
+	for i := range hash {
+		hash[i] = output[32+i] ^ h[32+i]
+	}
+	return hash
+}
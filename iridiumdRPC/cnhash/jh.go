@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, JH-256, one of the CryptoNight final hashes
+
+package cnhash
+
+import "encoding/binary"
+
+const jhRounds = 42
+
+// the two 4 bits S-boxes, each bit of the round constant selects one of them
+var jhSbox = [2][16]byte{
+	{9, 0, 4, 11, 13, 12, 3, 15, 1, 10, 2, 6, 7, 5, 8, 14},
+	{3, 12, 6, 13, 5, 7, 1, 9, 15, 2, 0, 4, 11, 10, 14, 8},
+}
+
+// first round constant, the 4 bits elements of the fractional part of sqrt(2)
+var jhRoundConstantZero = [64]byte{
+	0x6, 0xa, 0x0, 0x9, 0xe, 0x6, 0x6, 0x7, 0xf, 0x3, 0xb, 0xc, 0xc, 0x9, 0x0, 0x8,
+	0xb, 0x2, 0xf, 0xb, 0x1, 0x3, 0x6, 0x6, 0xe, 0xa, 0x9, 0x5, 0x7, 0xd, 0x3, 0xe,
+	0x3, 0xa, 0xd, 0xe, 0xc, 0x1, 0x7, 0x5, 0x1, 0x2, 0x7, 0x7, 0x5, 0x0, 0x9, 0x9,
+	0xd, 0xa, 0x2, 0xf, 0x5, 0x9, 0x0, 0xb, 0x0, 0x6, 0x6, 0x7, 0x3, 0x2, 0x2, 0xa,
+}
+
+// MDS layer on two 4 bits elements
+func jhL(a byte, b byte) (byte, byte) {
+	b ^= (a<<1 ^ a>>3 ^ (a>>2)&2) & 0xf
+	a ^= (b<<1 ^ b>>3 ^ (b>>2)&2) & 0xf
+	return a, b
+}
+
+// permutation layer of 2n elements : swap of the odd pairs, even elements first, swap of the pairs of the second half
+func jhPermute(elements []byte) []byte {
+	n := len(elements)
+	for i := 0; i < n; i += 4 {
+		elements[i+2], elements[i+3] = elements[i+3], elements[i+2]
+	}
+	permuted := make([]byte, n)
+	for i := 0; i < n/2; i++ {
+		permuted[i] = elements[2*i]
+		permuted[i+n/2] = elements[2*i+1]
+	}
+	for i := n / 2; i < n; i += 2 {
+		permuted[i], permuted[i+1] = permuted[i+1], permuted[i]
+	}
+	return permuted
+}
+
+// one round on the 256 elements of the state or the 64 of the round constant, selected by the bits of constant
+func jhRound(elements []byte, constant []byte) []byte {
+	substituted := make([]byte, len(elements))
+	for i, element := range elements {
+		var selector byte
+		if constant != nil {
+			selector = constant[i>>2] >> (3 - uint(i&3)) & 1
+		}
+		substituted[i] = jhSbox[selector][element]
+	}
+	for i := 0; i < len(substituted); i += 2 {
+		substituted[i], substituted[i+1] = jhL(substituted[i], substituted[i+1])
+	}
+	return jhPermute(substituted)
+}
+
+// bijective function E8 on the 1024 bits state
+func jhE8(h *[128]byte) {
+	bit := func(i int) byte {
+		return h[i>>3] >> (7 - uint(i&7)) & 1
+	}
+	// bits i, i+256, i+512 and i+768 make element i, even and odd elements from both halves
+	grouped := make([]byte, 256)
+	for i := 0; i < 256; i++ {
+		element := bit(i)<<3 | bit(i+256)<<2 | bit(i+512)<<1 | bit(i+768)
+		if i < 128 {
+			grouped[2*i] = element
+		} else {
+			grouped[2*(i-128)+1] = element
+		}
+	}
+
+	constant := jhRoundConstantZero[:]
+	for round := 0; round < jhRounds; round++ {
+		grouped = jhRound(grouped, constant)
+		constant = jhRound(constant, nil)
+	}
+
+	*h = [128]byte{}
+	for i := 0; i < 256; i++ {
+		var element byte
+		if i < 128 {
+			element = grouped[2*i]
+		} else {
+			element = grouped[2*(i-128)+1]
+		}
+		for j := 0; j < 4; j++ {
+			position := i + 256*j
+			h[position>>3] |= (element >> (3 - uint(j)) & 1) << (7 - uint(position&7))
+		}
+	}
+}
+
+func jhCompress(h *[128]byte, block []byte) {
+	for i := 0; i < 64; i++ {
+		h[i] ^= block[i]
+	}
+	jhE8(h)
+	for i := 0; i < 64; i++ {
+		h[i+64] ^= block[i]
+	}
+}
+
+// JH-256
+func jh256(data []byte) Hash {
+	var h [128]byte
+	h[0] = 0x01 // 256 bits output
+	jhCompress(&h, make([]byte, 64))
+
+	length := uint64(len(data)) * 8
+	for ; len(data) >= 64; data = data[64:] {
+		jhCompress(&h, data)
+	}
+
+	// a 1 bit, zeros and the length on 128 bits, at least a whole block
+	var last [128]byte
+	copy(last[:], data)
+	last[len(data)] = 0x80
+	size := 64
+	if len(data) > 0 {
+		size = 128
+	}
+	binary.BigEndian.PutUint64(last[size-8:], length)
+	for offset := 0; offset < size; offset += 64 {
+		jhCompress(&h, last[offset:offset+64])
+	}
+
+	var hash Hash
+	copy(hash[:], h[96:])
+	return hash
+}
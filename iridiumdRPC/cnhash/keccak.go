@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, Keccak as used by cn_fast_hash
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// HashSize, size of a CryptoNote hash
+const HashSize = 32
+
+// Hash, a CryptoNote hash
+type Hash [HashSize]byte
+
+// StateSize, size of the Keccak-f[1600] state
+const StateSize = 200
+
+// rate of keccak1600, the CryptoNote HASH_DATA_AREA
+const hashDataArea = 136
+
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+var rotations = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+
+var piLanes = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+
+// KeccakF, the Keccak-f[1600] permutation
+func KeccakF(state *[25]uint64) {
+	var bc [5]uint64
+	for round := 0; round < 24; round++ {
+		// theta
+		for i := 0; i < 5; i++ {
+			bc[i] = state[i] ^ state[i+5] ^ state[i+10] ^ state[i+15] ^ state[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			t := bc[(i+4)%5] ^ bits.RotateLeft64(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				state[j+i] ^= t
+			}
+		}
+		// rho pi
+		t := state[1]
+		for i := 0; i < 24; i++ {
+			j := piLanes[i]
+			bc[0] = state[j]
+			state[j] = bits.RotateLeft64(t, rotations[i])
+			t = bc[0]
+		}
+		// chi
+		for j := 0; j < 25; j += 5 {
+			for i := 0; i < 5; i++ {
+				bc[i] = state[j+i]
+			}
+			for i := 0; i < 5; i++ {
+				state[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+		// iota
+		state[0] ^= roundConstants[round]
+	}
+}
+
+// absorbs data with the original Keccak padding, rate bytes at a time
+func keccak(data []byte, rate int) [25]uint64 {
+	var state [25]uint64
+	for ; len(data) >= rate; data = data[rate:] {
+		for i := 0; i < rate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(data[i*8:])
+		}
+		KeccakF(&state)
+	}
+	var last [StateSize]byte
+	copy(last[:], data)
+	last[len(data)] = 1
+	last[rate-1] |= 0x80
+	for i := 0; i < rate/8; i++ {
+		state[i] ^= binary.LittleEndian.Uint64(last[i*8:])
+	}
+	KeccakF(&state)
+	return state
+}
+
+// StateBytes, the state as the little endian bytes CryptoNote works on
+func StateBytes(state *[25]uint64) [StateSize]byte {
+	var out [StateSize]byte
+	for i, lane := range state {
+		binary.LittleEndian.PutUint64(out[i*8:], lane)
+	}
+	return out
+}
+
+// Keccak1600, the whole Keccak state after absorbing data, CryptoNote keccak1600
+func Keccak1600(data []byte) [StateSize]byte {
+	state := keccak(data, hashDataArea)
+	return StateBytes(&state)
+}
+
+// FastHash, Keccak-256 with the original padding, CryptoNote cn_fast_hash
+func FastHash(data []byte) Hash {
+	state := keccak(data, StateSize-2*HashSize)
+	var hash Hash
+	bytes := StateBytes(&state)
+	copy(hash[:], bytes[:HashSize])
+	return hash
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing tests
+package cnhash
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Colorize output...
+const ok = "\033[32m[OK] : \033[0m"
+const er = "\033[31m[ERROR] : \033[0m"
+
+func TestFastHash(t *testing.T) {
+	vectors := []struct {
+		data string
+		hash string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+		// exactly one rate block, and more
+		{strings.Repeat("a", 136), "a6c4d403279fe3e0af03729caada8374b5ca54d8065329a3ebcaeb4b60aa386e"},
+		{strings.Repeat("a", 200), "96ea54061def936c4be90b518992fdc6f12f535068a256229aca54267b4d084d"},
+	}
+	for _, vector := range vectors {
+		hash := FastHash([]byte(vector.data))
+		if hex.EncodeToString(hash[:]) != vector.hash {
+			t.Errorf("%sFastHash(%.20q) = %x, want %s", er, vector.data, hash, vector.hash)
+		}
+	}
+	t.Logf("%s%d Keccak-256 vectors", ok, len(vectors))
+}
+
+func TestKeccak1600(t *testing.T) {
+	state := Keccak1600([]byte("This is a test"))
+	hash := FastHash([]byte("This is a test"))
+	if string(state[:HashSize]) != string(hash[:]) {
+		t.Errorf("%sKeccak1600 state doesn't start with the Keccak-256 hash : %x", er, state[:HashSize])
+	}
+}
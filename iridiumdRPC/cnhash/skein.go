@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote hashing, Skein-512-256, one of the CryptoNight final hashes
+
+package cnhash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	skeinKeyParity = 0x1bd11bdaa9fc1a22
+	// UBI block types
+	skeinTypeConfig  = 4
+	skeinTypeMessage = 48
+	skeinTypeOutput  = 63
+)
+
+var skeinRotations = [8][4]int{
+	{46, 36, 19, 37}, {33, 27, 14, 42}, {17, 49, 36, 39}, {44, 9, 54, 56},
+	{39, 30, 34, 24}, {13, 50, 10, 17}, {25, 29, 39, 43}, {8, 35, 56, 22},
+}
+
+// words permutation of Threefish-512 after each round
+var skeinPermutation = [8]int{2, 1, 4, 7, 6, 5, 0, 3}
+
+// Threefish-512 encryption of block with key and tweak, 72 rounds
+func threefish512(key *[8]uint64, tweak [2]uint64, block *[8]uint64) [8]uint64 {
+	var k [9]uint64
+	copy(k[:], key[:])
+	k[8] = skeinKeyParity
+	for _, word := range key {
+		k[8] ^= word
+	}
+	t := [3]uint64{tweak[0], tweak[1], tweak[0] ^ tweak[1]}
+
+	x := *block
+	for round := 0; round < 72; round++ {
+		if round%4 == 0 {
+			s := round / 4
+			for i := range x {
+				x[i] += k[(s+i)%9]
+			}
+			x[5] += t[s%3]
+			x[6] += t[(s+1)%3]
+			x[7] += uint64(s)
+		}
+		for j := 0; j < 4; j++ {
+			x[2*j] += x[2*j+1]
+			x[2*j+1] = bits.RotateLeft64(x[2*j+1], skeinRotations[round%8][j]) ^ x[2*j]
+		}
+		var permuted [8]uint64
+		for i, from := range skeinPermutation {
+			permuted[i] = x[from]
+		}
+		x = permuted
+	}
+	for i := range x {
+		x[i] += k[(18+i)%9]
+	}
+	x[5] += t[18%3]
+	x[6] += t[19%3]
+	x[7] += 18
+	return x
+}
+
+// UBI chaining of message blocks of one type, the last block zero padded
+func skeinUBI(h *[8]uint64, message []byte, blockType uint64) {
+	position := uint64(0)
+	first := uint64(1) << 62
+	for {
+		var block [64]byte
+		size := copy(block[:], message)
+		message = message[size:]
+		position += uint64(size)
+		tweak1 := blockType<<56 | first
+		if len(message) == 0 {
+			tweak1 |= 1 << 63
+		}
+		var words [8]uint64
+		for i := range words {
+			words[i] = binary.LittleEndian.Uint64(block[i*8:])
+		}
+		encrypted := threefish512(h, [2]uint64{position, tweak1}, &words)
+		for i := range h {
+			h[i] = encrypted[i] ^ words[i]
+		}
+		first = 0
+		if len(message) == 0 {
+			return
+		}
+	}
+}
+
+// Skein-512-256, the hash of the Skein-512 state with a 256 bits output
+func skein512256(data []byte) Hash {
+	var h [8]uint64
+	config := make([]byte, 32)
+	copy(config, "SHA3")
+	config[4] = 1                                  // version
+	binary.LittleEndian.PutUint64(config[8:], 256) // output bits
+	skeinUBI(&h, config, skeinTypeConfig)
+	skeinUBI(&h, data, skeinTypeMessage)
+	skeinUBI(&h, make([]byte, 8), skeinTypeOutput)
+
+	var hash Hash
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(hash[i*8:], h[i])
+	}
+	return hash
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API context tests
+package iridiumdRPC
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// returns a node targeting a test server which never answers, and its stop function
+func newStalledNode(t *testing.T) (*Iridiumd, func()) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+	stop := func() {
+		close(release)
+		server.Close()
+	}
+	return &Iridiumd{Address: host, Port: portNumber}, stop
+}
+
+func TestIridiumd_ContextCancel(t *testing.T) {
+	stalled, stop := newStalledNode(t)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	_, err := stalled.BlockDetailsContext(ctx, "9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43")
+	if err != context.Canceled {
+		t.Errorf("%swant %v, got %v", er, context.Canceled, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("%scancelled request returned after %s", er, elapsed)
+	}
+	t.Logf("%sGetBlockDetailsContext cancelled : %v", ok, err)
+}
+
+func TestIridiumd_ContextDeadline(t *testing.T) {
+	stalled, stop := newStalledNode(t)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := stalled.HeightContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("%swant %v, got %v", er, context.DeadlineExceeded, err)
+	}
+	if _, err := stalled.GetInfoContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("%swant %v, got %v", er, context.DeadlineExceeded, err)
+	}
+}
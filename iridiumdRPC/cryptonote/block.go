@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote blocks binary serialization and hashing
+
+package cryptonote
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+var (
+	// ErrInvalidBlock, the block blob can't be decoded
+	ErrInvalidBlock = errors.New("invalid block")
+	// ErrUnsupportedVersion, the block is of the merge mined major versions 2 and 3, it matches ErrInvalidBlock too
+	ErrUnsupportedVersion = fmt.Errorf("%w : unsupported major version", ErrInvalidBlock)
+)
+
+// CheckVersion, returns ErrUnsupportedVersion for the major versions 2 and 3, which carry the parent block of merge mining
+func CheckVersion(majorVersion uint8) error {
+	if majorVersion == 2 || majorVersion == 3 {
+		return fmt.Errorf("%w %d", ErrUnsupportedVersion, majorVersion)
+	}
+	return nil
+}
+
+// BlockHeader, the block fields before its transactions
+type BlockHeader struct {
+	MajorVersion uint8
+	MinorVersion uint8
+	Timestamp    uint64
+	PrevHash     cnhash.Hash
+	Nonce        uint32
+}
+
+// Block, the header, the miner transaction and the hashes of the other transactions
+type Block struct {
+	BlockHeader
+	MinerTx  Transaction
+	TxHashes []cnhash.Hash
+}
+
+// DecodeBlock, decodes a block blob, merge mined blocks of major versions 2 and 3 excepted
+func DecodeBlock(blob []byte) (*Block, error) {
+	r := &reader{data: blob}
+	block, err := readBlock(r)
+	if err == nil && r.remaining() > 0 {
+		err = fmt.Errorf("%d bytes after the block", r.remaining())
+	}
+	if err != nil && !errors.Is(err, ErrInvalidBlock) {
+		err = fmt.Errorf("%w : %v", ErrInvalidBlock, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// Bytes, the block blob
+func (block *Block) Bytes() []byte {
+	w := &writer{}
+	block.BlockHeader.write(w)
+	block.MinerTx.write(w)
+	w.varint(uint64(len(block.TxHashes)))
+	for _, hash := range block.TxHashes {
+		w.bytes(hash[:])
+	}
+	return w.data
+}
+
+// Hashes, the hashes of all the block transactions, the miner transaction first
+func (block *Block) Hashes() []cnhash.Hash {
+	return append([]cnhash.Hash{block.MinerTx.Hash()}, block.TxHashes...)
+}
+
+// MerkleRoot, tree hash of the block transactions
+func (block *Block) MerkleRoot() cnhash.Hash {
+	return TreeHash(block.Hashes())
+}
+
+// HashingBlob, the header, the merkle root and the transactions count, what is hashed by the block id and the PoW
+func (block *Block) HashingBlob() []byte {
+	return block.BlockHeader.HashingBlob(block.Hashes())
+}
+
+// Hash, the block id
+func (block *Block) Hash() cnhash.Hash {
+	return block.BlockHeader.Hash(block.Hashes())
+}
+
+// HashingBlob, hashing blob of the block with this header and these transactions, the miner transaction first
+func (header *BlockHeader) HashingBlob(txHashes []cnhash.Hash) []byte {
+	w := &writer{}
+	header.write(w)
+	root := TreeHash(txHashes)
+	w.bytes(root[:])
+	w.varint(uint64(len(txHashes)))
+	return w.data
+}
+
+// Hash, id of the block with this header and these transactions, Keccak of its hashing blob prefixed by its size
+func (header *BlockHeader) Hash(txHashes []cnhash.Hash) cnhash.Hash {
+	w := &writer{}
+	w.varBytes(header.HashingBlob(txHashes))
+	return cnhash.FastHash(w.data)
+}
+
+/*
+TreeHash, CryptoNote tree hash of hashes : the hashes beyond the largest power of 2 below their count
+are paired first, then the tree is reduced by pairs down to the root
+*/
+func TreeHash(hashes []cnhash.Hash) cnhash.Hash {
+	switch len(hashes) {
+	case 0:
+		return cnhash.Hash{}
+	case 1:
+		return hashes[0]
+	case 2:
+		return hashPair(hashes[0], hashes[1])
+	}
+	count := 1
+	for count*2 < len(hashes) {
+		count *= 2
+	}
+	level := make([]cnhash.Hash, count)
+	copied := copy(level, hashes[:2*count-len(hashes)])
+	for i, j := copied, copied; j < count; i, j = i+2, j+1 {
+		level[j] = hashPair(hashes[i], hashes[i+1])
+	}
+	for count > 2 {
+		count /= 2
+		for i := 0; i < count; i++ {
+			level[i] = hashPair(level[2*i], level[2*i+1])
+		}
+	}
+	return hashPair(level[0], level[1])
+}
+
+func hashPair(left cnhash.Hash, right cnhash.Hash) cnhash.Hash {
+	var pair [2 * cnhash.HashSize]byte
+	copy(pair[:], left[:])
+	copy(pair[cnhash.HashSize:], right[:])
+	return cnhash.FastHash(pair[:])
+}
+
+func readBlock(r *reader) (*Block, error) {
+	block := &Block{}
+	if err := block.BlockHeader.read(r); err != nil {
+		return nil, err
+	}
+	minerTx, err := readTransaction(r)
+	if err != nil {
+		return nil, fmt.Errorf("miner transaction : %v", err)
+	}
+	block.MinerTx = *minerTx
+	count, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	block.TxHashes = make([]cnhash.Hash, count)
+	for i := range block.TxHashes {
+		hash, err := r.bytes(cnhash.HashSize)
+		if err != nil {
+			return nil, err
+		}
+		copy(block.TxHashes[i][:], hash)
+	}
+	return block, nil
+}
+
+func (header *BlockHeader) read(r *reader) error {
+	var versions [2]uint64
+	for i := range versions {
+		version, err := r.varint()
+		if err != nil {
+			return err
+		}
+		if version > math.MaxUint8 {
+			return fmt.Errorf("version %d", version)
+		}
+		versions[i] = version
+	}
+	header.MajorVersion, header.MinorVersion = uint8(versions[0]), uint8(versions[1])
+	if err := CheckVersion(header.MajorVersion); err != nil {
+		return err
+	}
+	var err error
+	if header.Timestamp, err = r.varint(); err != nil {
+		return err
+	}
+	prevHash, err := r.bytes(cnhash.HashSize)
+	if err != nil {
+		return err
+	}
+	copy(header.PrevHash[:], prevHash)
+	nonce, err := r.bytes(4)
+	if err != nil {
+		return err
+	}
+	header.Nonce = binary.LittleEndian.Uint32(nonce)
+	return nil
+}
+
+func (header *BlockHeader) write(w *writer) {
+	w.varint(uint64(header.MajorVersion))
+	w.varint(uint64(header.MinorVersion))
+	w.varint(header.Timestamp)
+	w.bytes(header.PrevHash[:])
+	var nonce [4]byte
+	binary.LittleEndian.PutUint32(nonce[:], header.Nonce)
+	w.bytes(nonce[:])
+}
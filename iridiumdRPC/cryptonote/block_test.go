@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote blocks serialization and hashing tests
+package cryptonote
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+const (
+	genesisHash   = "9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43"
+	genesisTxHash = "f3fe271b4edceebf60a29d535a8dec957809baf4c69549a09ae113eb88a5f1ad"
+)
+
+func testHashes(n int) []cnhash.Hash {
+	hashes := make([]cnhash.Hash, n)
+	for i := range hashes {
+		hashes[i] = cnhash.FastHash([]byte{byte(i)})
+	}
+	return hashes
+}
+
+func TestBlockHeader_Hash(t *testing.T) {
+	genesis := BlockHeader{MajorVersion: 1, Nonce: 70}
+	var minerTx cnhash.Hash
+	hex.Decode(minerTx[:], []byte(genesisTxHash))
+
+	if blob := genesis.HashingBlob([]cnhash.Hash{minerTx}); len(blob) != 72 {
+		t.Errorf("%shashing blob of %d bytes, want 72", er, len(blob))
+	}
+	if hash := genesis.Hash([]cnhash.Hash{minerTx}); hex.EncodeToString(hash[:]) != genesisHash {
+		t.Errorf("%sgenesis hash %x, want %s", er, hash, genesisHash)
+	}
+	t.Logf("%s%s", ok, genesisHash)
+}
+
+func TestTreeHash(t *testing.T) {
+	h := testHashes(8)
+	vectors := []struct {
+		count int
+		root  cnhash.Hash
+	}{
+		{1, h[0]},
+		{2, hashPair(h[0], h[1])},
+		{3, hashPair(h[0], hashPair(h[1], h[2]))},
+		{4, hashPair(hashPair(h[0], h[1]), hashPair(h[2], h[3]))},
+		{5, hashPair(hashPair(h[0], h[1]), hashPair(h[2], hashPair(h[3], h[4])))},
+		{7, hashPair(hashPair(h[0], hashPair(h[1], h[2])), hashPair(hashPair(h[3], h[4]), hashPair(h[5], h[6])))},
+		{8, hashPair(hashPair(hashPair(h[0], h[1]), hashPair(h[2], h[3])), hashPair(hashPair(h[4], h[5]), hashPair(h[6], h[7])))},
+	}
+	for _, vector := range vectors {
+		if root := TreeHash(h[:vector.count]); root != vector.root {
+			t.Errorf("%stree hash of %d hashes %x, want %x", er, vector.count, root, vector.root)
+		}
+	}
+	t.Logf("%s%d tree hashes", ok, len(vectors))
+}
+
+func TestDecodeBlock(t *testing.T) {
+	coinbase, _ := hex.DecodeString(coinbaseBlob)
+	minerTx, _ := DecodeTransaction(coinbase)
+	block := &Block{
+		BlockHeader: BlockHeader{MajorVersion: 5, Timestamp: 1567540598, Nonce: 0xdeadbeef},
+		MinerTx:     *minerTx,
+		TxHashes:    testHashes(2),
+	}
+	block.PrevHash[0] = 0x3b
+	blob := block.Bytes()
+	// versions, 5 bytes of timestamp, prev_hash and nonce before the miner transaction
+	if len(blob) != 2+5+cnhash.HashSize+4+len(coinbase)+1+2*cnhash.HashSize {
+		t.Errorf("%sblob of %d bytes", er, len(blob))
+	}
+
+	decoded, err := DecodeBlock(blob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if !reflect.DeepEqual(decoded, block) {
+		t.Errorf("%sdecoded as %+v", er, decoded)
+	}
+	hashes := decoded.Hashes()
+	if hex.EncodeToString(hashes[0][:]) != coinbaseHash || decoded.MerkleRoot() != TreeHash(hashes) ||
+		decoded.Hash() != block.BlockHeader.Hash(hashes) {
+		t.Errorf("%sunexpected hashes", er)
+	}
+
+	invalid := map[string][]byte{
+		"truncated":      blob[:len(blob)-1],
+		"trailing bytes": append(append([]byte{}, blob...), 0),
+		"merge mined":    append([]byte{2}, blob[1:]...),
+		"miner tx":       blob[:50],
+	}
+	for name, data := range invalid {
+		if _, err := DecodeBlock(data); !errors.Is(err, ErrInvalidBlock) {
+			t.Errorf("%s%s : want %v, got %v", er, name, ErrInvalidBlock, err)
+		}
+	}
+	if _, err := DecodeBlock(invalid["merge mined"]); !errors.Is(err, ErrUnsupportedVersion) || err.Error() != "invalid block : unsupported major version 2" {
+		t.Errorf("%swant %v, got %v", er, ErrUnsupportedVersion, err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote transaction extra field
+
+package cryptonote
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+// tags of the extra fields
+const (
+	ExtraTagPadding     = 0x00
+	ExtraTagPublicKey   = 0x01
+	ExtraTagNonce       = 0x02
+	ExtraTagMergeMining = 0x03
+)
+
+// sub-tags of the extra nonce
+const (
+	ExtraNoncePaymentID          = 0x00
+	ExtraNonceEncryptedPaymentID = 0x01
+)
+
+// MaxExtraPadding, the most padding bytes an extra can hold
+const MaxExtraPadding = 255
+
+// ErrInvalidExtra, a known field of the extra is malformed
+var ErrInvalidExtra = errors.New("invalid transaction extra")
+
+// EncryptedPaymentID, the 8 bytes payment ID encrypted in the extra nonce
+type EncryptedPaymentID [8]byte
+
+func (paymentID EncryptedPaymentID) String() string {
+	return hex.EncodeToString(paymentID[:])
+}
+
+// MergeMiningTag, depth and merkle root of a merge mined block
+type MergeMiningTag struct {
+	Depth      uint64
+	MerkleRoot cnhash.Hash
+}
+
+/*
+Extra, the fields of a transaction extra, nil or zero when missing.
+Parsing stops at the first unknown tag, Unparsed holds the bytes from it to the end.
+*/
+type Extra struct {
+	PublicKey          *address.PublicKey
+	Nonce              []byte
+	PaymentID          *address.PaymentID
+	EncryptedPaymentID *EncryptedPaymentID
+	MergeMining        *MergeMiningTag
+	Padding            int
+	Unparsed           []byte
+}
+
+// ParseExtraHex, parses the hex encoded extra shown by f_transaction_json
+func ParseExtraHex(extra string) (*Extra, error) {
+	bytes, err := hex.DecodeString(extra)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidExtra, err)
+	}
+	return ParseExtra(bytes)
+}
+
+/*
+ParseExtra, parses the fields of a transaction extra, the first public key and nonce are kept like the node does.
+On a malformed field, the error wraps ErrInvalidExtra and the fields read before it are returned,
+with Unparsed starting at its tag.
+*/
+func ParseExtra(extra []byte) (*Extra, error) {
+	parsed := &Extra{}
+	r := &reader{data: extra}
+	for r.remaining() > 0 {
+		start := r.offset
+		tag, _ := r.byte()
+		var err error
+		switch tag {
+		case ExtraTagPadding:
+			err = parsed.readPadding(r)
+		case ExtraTagPublicKey:
+			err = parsed.readPublicKey(r)
+		case ExtraTagNonce:
+			err = parsed.readNonce(r)
+		case ExtraTagMergeMining:
+			err = parsed.readMergeMining(r)
+		default:
+			parsed.Unparsed = extra[start:]
+			return parsed, nil
+		}
+		if err != nil {
+			parsed.Unparsed = extra[start:]
+			return parsed, fmt.Errorf("%w : tag %#02x at offset %d : %v", ErrInvalidExtra, tag, start, err)
+		}
+	}
+	return parsed, nil
+}
+
+// padding is made of zeros, its tag included
+func (extra *Extra) readPadding(r *reader) error {
+	size := 1
+	for r.remaining() > 0 && r.data[r.offset] == 0 {
+		r.offset++
+		size++
+	}
+	if size > MaxExtraPadding {
+		return fmt.Errorf("%d bytes of padding", size)
+	}
+	extra.Padding += size
+	return nil
+}
+
+func (extra *Extra) readPublicKey(r *reader) error {
+	bytes, err := r.bytes(address.KeySize)
+	if err != nil {
+		return err
+	}
+	if extra.PublicKey == nil {
+		extra.PublicKey = new(address.PublicKey)
+		copy(extra.PublicKey[:], bytes)
+	}
+	return nil
+}
+
+// the nonce size is a single byte
+func (extra *Extra) readNonce(r *reader) error {
+	size, err := r.byte()
+	if err != nil {
+		return err
+	}
+	nonce, err := r.bytes(int(size))
+	if err != nil {
+		return err
+	}
+	if extra.Nonce != nil {
+		return nil
+	}
+	extra.Nonce = nonce
+	switch {
+	case len(nonce) == 1+address.PaymentIDSize && nonce[0] == ExtraNoncePaymentID:
+		extra.PaymentID = new(address.PaymentID)
+		copy(extra.PaymentID[:], nonce[1:])
+	case len(nonce) == 1+len(EncryptedPaymentID{}) && nonce[0] == ExtraNonceEncryptedPaymentID:
+		extra.EncryptedPaymentID = new(EncryptedPaymentID)
+		copy(extra.EncryptedPaymentID[:], nonce[1:])
+	}
+	return nil
+}
+
+// a varint size, then the depth varint and the merkle root
+func (extra *Extra) readMergeMining(r *reader) error {
+	field, err := r.varBytes()
+	if err != nil {
+		return err
+	}
+	tag := &reader{data: field}
+	depth, err := tag.varint()
+	if err != nil {
+		return err
+	}
+	root, err := tag.bytes(cnhash.HashSize)
+	if err != nil {
+		return err
+	}
+	extra.MergeMining = &MergeMiningTag{Depth: depth}
+	copy(extra.MergeMining.MerkleRoot[:], root)
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote transaction extra tests
+package cryptonote
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Colorize output...
+const ok = "\033[32m[OK] : \033[0m"
+const er = "\033[31m[ERROR] : \033[0m"
+
+// extra of the coinbase transaction of block 357782 : public key and miner nonce
+const coinbaseExtra = "01e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000"
+
+const (
+	testPublicKey = "e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad"
+	testPaymentID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestParseExtra(t *testing.T) {
+	extra, err := ParseExtraHex(coinbaseExtra)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if extra.PublicKey == nil || extra.PublicKey.String() != testPublicKey {
+		t.Errorf("%spublic key %v", er, extra.PublicKey)
+	}
+	if hex.EncodeToString(extra.Nonce) != "00000000e53a8eb3000000000000000000" || extra.PaymentID != nil {
+		t.Errorf("%snonce %x, payment ID %v", er, extra.Nonce, extra.PaymentID)
+	}
+	t.Logf("%s%s", ok, extra.PublicKey)
+
+	// payment ID nonce, merge mining tag and padding
+	extra, err = ParseExtraHex("022100" + testPaymentID + "01" + testPublicKey +
+		"0321" + "05" + strings.Repeat("ab", 32) + "000000")
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if extra.PaymentID == nil || extra.PaymentID.String() != testPaymentID {
+		t.Errorf("%spayment ID %v", er, extra.PaymentID)
+	}
+	if extra.MergeMining == nil || extra.MergeMining.Depth != 5 || extra.MergeMining.MerkleRoot[31] != 0xab {
+		t.Errorf("%smerge mining tag %+v", er, extra.MergeMining)
+	}
+	if extra.PublicKey == nil || extra.Padding != 3 || extra.Unparsed != nil {
+		t.Errorf("%sunexpected extra %+v", er, extra)
+	}
+
+	extra, err = ParseExtraHex("0209010102030405060708")
+	if err != nil || extra.EncryptedPaymentID == nil || extra.EncryptedPaymentID.String() != "0102030405060708" {
+		t.Errorf("%sencrypted payment ID %v, %v", er, extra.EncryptedPaymentID, err)
+	}
+}
+
+func TestParseExtra_Unparsed(t *testing.T) {
+	// unknown tags end the parsing without error
+	extra, err := ParseExtraHex("01" + testPublicKey + "de0102" + "022100" + testPaymentID)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if extra.PublicKey == nil || extra.PaymentID != nil || hex.EncodeToString(extra.Unparsed[:3]) != "de0102" {
+		t.Errorf("%sunexpected extra %+v", er, extra)
+	}
+
+	// padding followed by data
+	extra, err = ParseExtraHex("0000ff")
+	if err != nil || extra.Padding != 2 || hex.EncodeToString(extra.Unparsed) != "ff" {
+		t.Errorf("%sunexpected extra %+v, %v", er, extra, err)
+	}
+
+	// malformed fields return what was read before them
+	for _, malformed := range []string{
+		"01" + testPublicKey + "01e3a1b6",
+		"01" + testPublicKey + "0221" + testPaymentID,
+		"01" + testPublicKey + "03220a",
+		"01" + testPublicKey + strings.Repeat("00", MaxExtraPadding+1),
+	} {
+		extra, err := ParseExtraHex(malformed)
+		if !errors.Is(err, ErrInvalidExtra) {
+			t.Errorf("%swant %v, got %v", er, ErrInvalidExtra, err)
+			continue
+		}
+		if extra.PublicKey == nil || len(extra.Unparsed) != len(malformed)/2-1-32 {
+			t.Errorf("%sunexpected extra %+v", er, extra)
+		}
+	}
+	if _, err := ParseExtraHex("0x"); !errors.Is(err, ErrInvalidExtra) {
+		t.Errorf("%swant %v, got %v", er, ErrInvalidExtra, err)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium blocks proof of work
+
+package cryptonote
+
+import "github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+
+// the major version from which Iridium blocks are hashed with the variant 1 tweaks
+const powVariant1MajorVersion = 4
+
+// PoWVariant, the CryptoNight variant of the Iridium blocks of a major version
+func PoWVariant(majorVersion uint8) cnhash.Variant {
+	if majorVersion >= powVariant1MajorVersion {
+		return cnhash.CryptoNightLiteV1
+	}
+	return cnhash.CryptoNightLite
+}
+
+// PoWHash, CryptoNight hash of the hashing blob of the block with this header and these transactions
+func (header *BlockHeader) PoWHash(txHashes []cnhash.Hash) (cnhash.Hash, error) {
+	if err := CheckVersion(header.MajorVersion); err != nil {
+		return cnhash.Hash{}, err
+	}
+	return PoWVariant(header.MajorVersion).Sum(header.HashingBlob(txHashes))
+}
+
+// CheckPoW, true when the PoW hash of the block with this header and these transactions meets difficulty
+func (header *BlockHeader) CheckPoW(txHashes []cnhash.Hash, difficulty uint64) (bool, error) {
+	hash, err := header.PoWHash(txHashes)
+	if err != nil {
+		return false, err
+	}
+	return cnhash.CheckDifficulty(hash, difficulty), nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium blocks proof of work tests
+package cryptonote
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+// largest difficulty met by hash, (2^256 - 1) / hash with the hash read as a little endian number
+func maxDifficulty(hash cnhash.Hash) uint64 {
+	reversed := make([]byte, len(hash))
+	for i := range hash {
+		reversed[len(hash)-1-i] = hash[i]
+	}
+	limit := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	return limit.Div(limit, new(big.Int).SetBytes(reversed)).Uint64()
+}
+
+func TestBlockHeader_CheckPoW(t *testing.T) {
+	genesis := BlockHeader{MajorVersion: 1, Nonce: 70}
+	var minerTx cnhash.Hash
+	hex.Decode(minerTx[:], []byte(genesisTxHash))
+	txHashes := []cnhash.Hash{minerTx}
+
+	hash, err := genesis.PoWHash(txHashes)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	difficulty := maxDifficulty(hash)
+	if met, err := genesis.CheckPoW(txHashes, difficulty); !met || err != nil {
+		t.Errorf("%sdifficulty %d not met, %v", er, difficulty, err)
+	}
+	if met, _ := genesis.CheckPoW(txHashes, difficulty+1); met {
+		t.Errorf("%sdifficulty %d met", er, difficulty+1)
+	}
+	t.Logf("%sgenesis PoW hash %x, difficulty up to %d", ok, hash, difficulty)
+
+	if _, err := (&BlockHeader{MajorVersion: 2}).PoWHash(txHashes); err == nil {
+		t.Errorf("%smerge mined header hashed", er)
+	}
+}
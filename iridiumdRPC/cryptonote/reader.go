@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote binary serialization reader
+
+package cryptonote
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrTruncated, the data ends before the field read
+var ErrTruncated = errors.New("unexpected end of data")
+
+// reads CryptoNote binary serialization : varints, fixed size fields and byte strings
+type reader struct {
+	data   []byte
+	offset int
+}
+
+func (r *reader) remaining() int {
+	return len(r.data) - r.offset
+}
+
+func (r *reader) rest() []byte {
+	return r.data[r.offset:]
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > r.remaining() {
+		return nil, fmt.Errorf("%w : %d bytes at offset %d", ErrTruncated, n, r.offset)
+	}
+	bytes := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return bytes, nil
+}
+
+func (r *reader) byte() (byte, error) {
+	bytes, err := r.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return bytes[0], nil
+}
+
+func (r *reader) varint() (uint64, error) {
+	value, size := binary.Uvarint(r.rest())
+	if size == 0 {
+		return 0, fmt.Errorf("%w : varint at offset %d", ErrTruncated, r.offset)
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("varint overflow at offset %d", r.offset)
+	}
+	r.offset += size
+	return value, nil
+}
+
+// a varint size followed by as many bytes
+func (r *reader) varBytes() ([]byte, error) {
+	size, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(r.remaining()) {
+		return nil, fmt.Errorf("%w : %d bytes at offset %d", ErrTruncated, size, r.offset)
+	}
+	return r.bytes(int(size))
+}
+
+func (r *reader) uint32() (uint32, error) {
+	value, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	if value > math.MaxUint32 {
+		return 0, fmt.Errorf("%d overflows 32 bits at offset %d", value, r.offset)
+	}
+	return uint32(value), nil
+}
+
+// size of a vector, each element takes at least a byte
+func (r *reader) count() (int, error) {
+	count, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	if count > uint64(r.remaining()) {
+		return 0, fmt.Errorf("%w : %d elements at offset %d", ErrTruncated, count, r.offset)
+	}
+	return int(count), nil
+}
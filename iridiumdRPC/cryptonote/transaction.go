@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote transactions binary serialization
+
+package cryptonote
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+)
+
+// tags of the inputs and outputs
+const (
+	InputGen  = 0xff
+	InputKey  = 0x02
+	OutputKey = 0x02
+)
+
+// ErrInvalidTransaction, the transaction blob can't be decoded
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// KeyImage, key image of a key input
+type KeyImage [32]byte
+
+func (image KeyImage) String() string {
+	return hex.EncodeToString(image[:])
+}
+
+// Signature, one signature of a ring signature
+type Signature [64]byte
+
+func (signature Signature) String() string {
+	return hex.EncodeToString(signature[:])
+}
+
+// Input, a gen input (InputGen) has a height, a key input (InputKey) an amount, key offsets and a key image
+type Input struct {
+	Type       byte
+	Height     uint32
+	Amount     uint64
+	KeyOffsets []uint32
+	KeyImage   KeyImage
+}
+
+// Output, an amount sent to a one time public key
+type Output struct {
+	Amount uint64
+	Key    address.PublicKey
+}
+
+// TransactionPrefix, the transaction without its signatures
+type TransactionPrefix struct {
+	Version    uint8
+	UnlockTime uint64
+	Inputs     []Input
+	Outputs    []Output
+	Extra      []byte
+}
+
+// Transaction, the prefix and the ring signature of each input, with one signature by key offset
+type Transaction struct {
+	TransactionPrefix
+	Signatures [][]Signature
+}
+
+// DecodeTransaction, decodes a transaction blob, as found in txs_as_hex
+func DecodeTransaction(blob []byte) (*Transaction, error) {
+	r := &reader{data: blob}
+	tx, err := readTransaction(r)
+	if err == nil && r.remaining() > 0 {
+		err = fmt.Errorf("%d bytes after the transaction", r.remaining())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidTransaction, err)
+	}
+	return tx, nil
+}
+
+// Bytes, the transaction blob
+func (tx *Transaction) Bytes() []byte {
+	w := &writer{}
+	tx.write(w)
+	return w.data
+}
+
+// Hash, the transaction hash, Keccak of its blob
+func (tx *Transaction) Hash() cnhash.Hash {
+	return cnhash.FastHash(tx.Bytes())
+}
+
+// Hash, the prefix hash, the message of the ring signatures
+func (prefix *TransactionPrefix) Hash() cnhash.Hash {
+	w := &writer{}
+	prefix.write(w)
+	return cnhash.FastHash(w.data)
+}
+
+func readTransaction(r *reader) (*Transaction, error) {
+	tx := &Transaction{}
+	if err := tx.TransactionPrefix.read(r); err != nil {
+		return nil, err
+	}
+	tx.Signatures = make([][]Signature, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		if input.Type != InputKey {
+			continue
+		}
+		tx.Signatures[i] = make([]Signature, len(input.KeyOffsets))
+		for j := range tx.Signatures[i] {
+			bytes, err := r.bytes(len(Signature{}))
+			if err != nil {
+				return nil, err
+			}
+			copy(tx.Signatures[i][j][:], bytes)
+		}
+	}
+	return tx, nil
+}
+
+func (tx *Transaction) write(w *writer) {
+	tx.TransactionPrefix.write(w)
+	for _, signatures := range tx.Signatures {
+		for _, signature := range signatures {
+			w.bytes(signature[:])
+		}
+	}
+}
+
+func (prefix *TransactionPrefix) read(r *reader) error {
+	version, err := r.varint()
+	if err != nil {
+		return err
+	}
+	if version > math.MaxUint8 {
+		return fmt.Errorf("version %d", version)
+	}
+	prefix.Version = uint8(version)
+	if prefix.UnlockTime, err = r.varint(); err != nil {
+		return err
+	}
+
+	count, err := r.count()
+	if err != nil {
+		return err
+	}
+	prefix.Inputs = make([]Input, count)
+	for i := range prefix.Inputs {
+		if err := prefix.Inputs[i].read(r); err != nil {
+			return fmt.Errorf("input %d : %v", i, err)
+		}
+	}
+
+	if count, err = r.count(); err != nil {
+		return err
+	}
+	prefix.Outputs = make([]Output, count)
+	for i := range prefix.Outputs {
+		if err := prefix.Outputs[i].read(r); err != nil {
+			return fmt.Errorf("output %d : %v", i, err)
+		}
+	}
+
+	prefix.Extra, err = r.varBytes()
+	return err
+}
+
+func (prefix *TransactionPrefix) write(w *writer) {
+	w.varint(uint64(prefix.Version))
+	w.varint(prefix.UnlockTime)
+	w.varint(uint64(len(prefix.Inputs)))
+	for i := range prefix.Inputs {
+		prefix.Inputs[i].write(w)
+	}
+	w.varint(uint64(len(prefix.Outputs)))
+	for i := range prefix.Outputs {
+		prefix.Outputs[i].write(w)
+	}
+	w.varBytes(prefix.Extra)
+}
+
+func (input *Input) read(r *reader) error {
+	var err error
+	if input.Type, err = r.byte(); err != nil {
+		return err
+	}
+	switch input.Type {
+	case InputGen:
+		input.Height, err = r.uint32()
+		return err
+	case InputKey:
+		if input.Amount, err = r.varint(); err != nil {
+			return err
+		}
+		count, err := r.count()
+		if err != nil {
+			return err
+		}
+		input.KeyOffsets = make([]uint32, count)
+		for i := range input.KeyOffsets {
+			if input.KeyOffsets[i], err = r.uint32(); err != nil {
+				return err
+			}
+		}
+		image, err := r.bytes(len(KeyImage{}))
+		if err != nil {
+			return err
+		}
+		copy(input.KeyImage[:], image)
+		return nil
+	default:
+		return fmt.Errorf("unsupported input type %#02x", input.Type)
+	}
+}
+
+func (input *Input) write(w *writer) {
+	w.byte(input.Type)
+	switch input.Type {
+	case InputGen:
+		w.varint(uint64(input.Height))
+	case InputKey:
+		w.varint(input.Amount)
+		w.varint(uint64(len(input.KeyOffsets)))
+		for _, offset := range input.KeyOffsets {
+			w.varint(uint64(offset))
+		}
+		w.bytes(input.KeyImage[:])
+	}
+}
+
+func (output *Output) read(r *reader) error {
+	var err error
+	if output.Amount, err = r.varint(); err != nil {
+		return err
+	}
+	target, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if target != OutputKey {
+		return fmt.Errorf("unsupported output type %#02x", target)
+	}
+	key, err := r.bytes(address.KeySize)
+	if err != nil {
+		return err
+	}
+	copy(output.Key[:], key)
+	return nil
+}
+
+func (output *Output) write(w *writer) {
+	w.varint(output.Amount)
+	w.byte(OutputKey)
+	w.bytes(output.Key[:])
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote transactions serialization tests
+package cryptonote
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// coinbase transaction ba29fad8... of block 357782
+const (
+	coinbaseHash = "ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2"
+	coinbaseBlob = "01aaeb1501ff96eb1507f101025f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58a846024b40a1f97a" +
+		"411881321022de6a8f532f30c4c404c39c345e26250e162935af79c0cf2402c7c6b07057750fff3ba1520ffbd71aeeb080f796315764ac20" +
+		"21f7f28d064b42c09fab0302638e1cc733f3566091bfbf696c680264a53dec7e75f82b5d09bd96b7e0e788d08087a70e02fc0b8cb0e7bf0a" +
+		"7fd8bee0765add136a03b72ddca9c5bd83b755ac3900cc48228088debe0102e9caba0c8f62238ebe81997faa69212c26a21d26199f2c2ba3" +
+		"aa125e3ec33c2c80a8d6b90702d1592617829f57545c0929e4cb286c019da0dd1d1910b7531a25a396466b0bda3401e3a1b6040ba3076335" +
+		"83398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000"
+)
+
+// a transaction spending two key inputs, with rings of 3 and 1
+func keyTransaction() *Transaction {
+	tx := &Transaction{}
+	tx.Version = 1
+	tx.Inputs = []Input{
+		{Type: InputKey, Amount: 5000, KeyOffsets: []uint32{12, 300, 70000}, KeyImage: KeyImage{1}},
+		{Type: InputKey, Amount: 200, KeyOffsets: []uint32{4}, KeyImage: KeyImage{2}},
+	}
+	tx.Outputs = []Output{{Amount: 5100}, {Amount: 90}}
+	tx.Extra, _ = hex.DecodeString("01" + testPublicKey)
+	tx.Signatures = [][]Signature{{{1}, {2}, {3}}, {{4}}}
+	return tx
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	blob, _ := hex.DecodeString(coinbaseBlob)
+	tx, err := DecodeTransaction(blob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if tx.Version != 1 || tx.UnlockTime != 357802 || len(tx.Inputs) != 1 || tx.Inputs[0].Type != InputGen ||
+		tx.Inputs[0].Height != 357782 || len(tx.Outputs) != 7 || tx.Outputs[6].Amount != 2000000000 ||
+		tx.Outputs[0].Key.String() != "5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58" ||
+		hex.EncodeToString(tx.Extra) != coinbaseExtra || len(tx.Signatures) != 1 || len(tx.Signatures[0]) != 0 {
+		t.Errorf("%sunexpected transaction %+v", er, tx)
+	}
+	if hash := tx.Hash(); hex.EncodeToString(hash[:]) != coinbaseHash {
+		t.Errorf("%shash %x, want %s", er, hash, coinbaseHash)
+	}
+	if !bytes.Equal(tx.Bytes(), blob) {
+		t.Errorf("%sserialized as %x", er, tx.Bytes())
+	}
+	t.Logf("%s%s, %d bytes", ok, coinbaseHash, len(blob))
+
+	// key inputs and their ring signatures
+	blob = keyTransaction().Bytes()
+	tx, err = DecodeTransaction(blob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	want := keyTransaction()
+	if len(tx.Inputs) != 2 || tx.Inputs[0].KeyOffsets[2] != 70000 || tx.Inputs[1].KeyImage != want.Inputs[1].KeyImage ||
+		len(tx.Signatures[0]) != 3 || tx.Signatures[0][2] != want.Signatures[0][2] || tx.Signatures[1][0] != want.Signatures[1][0] {
+		t.Errorf("%sunexpected transaction %+v", er, tx)
+	}
+	if tx.Hash() != want.Hash() || tx.TransactionPrefix.Hash() == tx.Hash() {
+		t.Errorf("%sunexpected hashes", er)
+	}
+}
+
+func TestDecodeTransaction_Invalid(t *testing.T) {
+	blob := keyTransaction().Bytes()
+	invalid := map[string][]byte{
+		"empty":             nil,
+		"truncated":         blob[:len(blob)-1],
+		"trailing bytes":    append(append([]byte{}, blob...), 0),
+		"unsupported input": {0x01, 0x00, 0x01, 0x03, 0x00, 0x00, 0x00},
+		"huge vector":       {0x01, 0x00, 0xff, 0xff, 0xff, 0xff, 0x0f},
+	}
+	for name, data := range invalid {
+		if _, err := DecodeTransaction(data); !errors.Is(err, ErrInvalidTransaction) {
+			t.Errorf("%s%s : want %v, got %v", er, name, ErrInvalidTransaction, err)
+		}
+	}
+}
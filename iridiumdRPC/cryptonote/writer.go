@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium CryptoNote binary serialization writer
+
+package cryptonote
+
+import "encoding/binary"
+
+// writes CryptoNote binary serialization, the counterpart of reader
+type writer struct {
+	data []byte
+}
+
+func (w *writer) bytes(bytes []byte) {
+	w.data = append(w.data, bytes...)
+}
+
+func (w *writer) byte(b byte) {
+	w.data = append(w.data, b)
+}
+
+func (w *writer) varint(value uint64) {
+	var buffer [binary.MaxVarintLen64]byte
+	w.data = append(w.data, buffer[:binary.PutUvarint(buffer[:], value)]...)
+}
+
+// a varint size followed by the bytes
+func (w *writer) varBytes(bytes []byte) {
+	w.varint(uint64(len(bytes)))
+	w.bytes(bytes)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transaction blobs decoding
+
+package iridiumdRPC
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+// DecodeTransaction, decodes a txs_as_hex blob into the f_transaction_json transaction, and returns its hash
+func DecodeTransaction(txHex string) (*Transaction, string, error) {
+	blob, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w : %v", cryptonote.ErrInvalidTransaction, err)
+	}
+	decoded, err := cryptonote.DecodeTransaction(blob)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx := &Transaction{
+		Version:    decoded.Version,
+		UnlockTime: decoded.UnlockTime,
+		Vin:        make([]TransactionInput, len(decoded.Inputs)),
+		Vout:       make([]TransactionOutput, len(decoded.Outputs)),
+		Extra:      hex.EncodeToString(decoded.Extra),
+	}
+	for i, input := range decoded.Inputs {
+		tx.Vin[i].Type = fmt.Sprintf("%02x", input.Type)
+		switch input.Type {
+		case cryptonote.InputGen:
+			tx.Vin[i].Value.Height = input.Height
+		case cryptonote.InputKey:
+			tx.Vin[i].Value.Amount = Amount(input.Amount)
+			tx.Vin[i].Value.KeyOffsets = input.KeyOffsets
+			tx.Vin[i].Value.KeyImage = input.KeyImage.String()
+		}
+	}
+	for i, output := range decoded.Outputs {
+		tx.Vout[i].Amount = Amount(output.Amount)
+		tx.Vout[i].Target.Type = fmt.Sprintf("%02x", cryptonote.OutputKey)
+		tx.Vout[i].Target.Data.Key = output.Key.String()
+	}
+
+	hash := cnhash.FastHash(blob)
+	return tx, hex.EncodeToString(hash[:]), nil
+}
+
+// Decode, decodes the txs_as_hex blobs, by transaction hash
+func (txs *Transactions) Decode() (map[string]*Transaction, error) {
+	decoded := make(map[string]*Transaction, len(txs.TxsAsHex))
+	for i, txHex := range txs.TxsAsHex {
+		tx, hash, err := DecodeTransaction(txHex)
+		if err != nil {
+			return nil, fmt.Errorf("txs_as_hex[%d] : %w", i, err)
+		}
+		decoded[hash] = tx
+	}
+	return decoded, nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transaction blobs decoding tests
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+// coinbase transaction of block 357782, as a blob and as shown by f_transaction_json
+const (
+	coinbaseHash = "ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2"
+	coinbaseBlob = "01aaeb1501ff96eb1507f101025f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58a846024b40a1f97a" +
+		"411881321022de6a8f532f30c4c404c39c345e26250e162935af79c0cf2402c7c6b07057750fff3ba1520ffbd71aeeb080f796315764ac20" +
+		"21f7f28d064b42c09fab0302638e1cc733f3566091bfbf696c680264a53dec7e75f82b5d09bd96b7e0e788d08087a70e02fc0b8cb0e7bf0a" +
+		"7fd8bee0765add136a03b72ddca9c5bd83b755ac3900cc48228088debe0102e9caba0c8f62238ebe81997faa69212c26a21d26199f2c2ba3" +
+		"aa125e3ec33c2c80a8d6b90702d1592617829f57545c0929e4cb286c019da0dd1d1910b7531a25a396466b0bda3401e3a1b6040ba3076335" +
+		"83398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000"
+	coinbaseJSON = `{"extra":"01e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000",
+		"unlock_time":357802,"version":1,"vin":[{"type":"ff","value":{"height":357782}}],"vout":[
+		{"amount":241,"target":{"data":{"key":"5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58"},"type":"02"}},
+		{"amount":9000,"target":{"data":{"key":"4b40a1f97a411881321022de6a8f532f30c4c404c39c345e26250e162935af79"},"type":"02"}},
+		{"amount":600000,"target":{"data":{"key":"c7c6b07057750fff3ba1520ffbd71aeeb080f796315764ac2021f7f28d064b42"},"type":"02"}},
+		{"amount":7000000,"target":{"data":{"key":"638e1cc733f3566091bfbf696c680264a53dec7e75f82b5d09bd96b7e0e788d0"},"type":"02"}},
+		{"amount":30000000,"target":{"data":{"key":"fc0b8cb0e7bf0a7fd8bee0765add136a03b72ddca9c5bd83b755ac3900cc4822"},"type":"02"}},
+		{"amount":400000000,"target":{"data":{"key":"e9caba0c8f62238ebe81997faa69212c26a21d26199f2c2ba3aa125e3ec33c2c"},"type":"02"}},
+		{"amount":2000000000,"target":{"data":{"key":"d1592617829f57545c0929e4cb286c019da0dd1d1910b7531a25a396466b0bda"},"type":"02"}}]}`
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	tx, hash, err := DecodeTransaction(coinbaseBlob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	var want Transaction
+	if err := json.Unmarshal([]byte(coinbaseJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	if hash != coinbaseHash || !reflect.DeepEqual(*tx, want) {
+		t.Errorf("%sdecoded %s as %+v, want %+v", er, hash, tx, want)
+	}
+	t.Logf("%s%s", ok, hash)
+
+	txs := &Transactions{TxsAsHex: []string{coinbaseBlob}}
+	decoded, err := txs.Decode()
+	if err != nil || len(decoded) != 1 || decoded[coinbaseHash] == nil {
+		t.Errorf("%sdecoded as %v, %v", er, decoded, err)
+	}
+	txs.TxsAsHex = append(txs.TxsAsHex, "01zz")
+	if _, err := txs.Decode(); !errors.Is(err, cryptonote.ErrInvalidTransaction) {
+		t.Errorf("%swant %v, got %v", er, cryptonote.ErrInvalidTransaction, err)
+	}
+}
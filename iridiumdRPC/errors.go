@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API errors
+
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// sentinel errors, use errors.Is to check them
+var (
+	// ErrIDMismatch, the response id is not the request id
+	ErrIDMismatch = errors.New("ids doesn't match")
+	// ErrEmptyBody, the node answered without a body
+	ErrEmptyBody = errors.New("body is empty")
+	// ErrEmptyResult, the JSON RPC response has neither a result nor an error
+	ErrEmptyResult = errors.New("result is empty")
+	// ErrNodeBusy, the node answered with status "BUSY", mostly while syncing
+	ErrNodeBusy = errors.New("node is busy")
+	// ErrTooBigReserveSize, the reserve size asked to getblocktemplate is above MaxReserveSize
+	ErrTooBigReserveSize = errors.New("reserve size is too big")
+	// ErrWrongWalletAddress, the node can't parse the wallet address given to getblocktemplate
+	ErrWrongWalletAddress = errors.New("wrong wallet address")
+	// ErrWrongBlockBlob, the block blob given to submitblock can't be parsed
+	ErrWrongBlockBlob = errors.New("wrong block blob")
+	// ErrBlockNotAccepted, the node rejected the submitted block
+	ErrBlockNotAccepted = errors.New("block not accepted")
+	// ErrTxRejected, the node refused the transaction sent by sendrawtransaction, see TxRejectedError
+	ErrTxRejected = errors.New("transaction rejected")
+	// ErrDoubleSpend, a key image of the transaction is already spent
+	ErrDoubleSpend = errors.New("double spend")
+	// ErrFeeTooLow, the transaction fee is under the node minimum
+	ErrFeeTooLow = errors.New("fee too low")
+	// ErrTxTooBig, the transaction is over the size the node accepts
+	ErrTxTooBig = errors.New("transaction too big")
+	// ErrTxNotRelayed, the node accepted the transaction but doesn't relay it
+	ErrTxNotRelayed = errors.New("transaction not relayed")
+)
+
+// JSON RPC error codes sent by the daemon
+const (
+	ErrorCodeParse              = -32700
+	ErrorCodeInvalidRequest     = -32600
+	ErrorCodeMethodNotFound     = -32601
+	ErrorCodeInvalidParams      = -32602
+	ErrorCodeInternal           = -32603
+	ErrorCodeWrongParam         = -1
+	ErrorCodeTooBigHeight       = -2
+	ErrorCodeTooBigReserveSize  = -3
+	ErrorCodeWrongWalletAddress = -4
+	ErrorCodeInternalError      = -5
+	ErrorCodeWrongBlockBlob     = -6
+	ErrorCodeBlockNotAccepted   = -7
+	ErrorCodeCoreBusy           = -9
+)
+
+// RPCError, error object of a JSON RPC response
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return "json rpc error " + strconv.Itoa(e.Code) + " : " + e.Message
+}
+
+// sentinel errors matched by the daemon error codes
+var codeErrors = map[int]error{
+	ErrorCodeTooBigReserveSize:  ErrTooBigReserveSize,
+	ErrorCodeWrongWalletAddress: ErrWrongWalletAddress,
+	ErrorCodeWrongBlockBlob:     ErrWrongBlockBlob,
+	ErrorCodeBlockNotAccepted:   ErrBlockNotAccepted,
+	ErrorCodeCoreBusy:           ErrNodeBusy,
+}
+
+// Is, an error code matches its sentinel error : core busy is ErrNodeBusy, block not accepted ErrBlockNotAccepted...
+func (e *RPCError) Is(target error) bool {
+	sentinel, found := codeErrors[e.Code]
+	return found && target == sentinel
+}
+
+// HTTPError, the node answered with an http status other than 200 OK
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+	}
+	return "Server response : " + status
+}
+
+/*
+TxRejectedError, status of a transaction refused by the node, it matches ErrTxRejected and its Reason when known,
+the reason is best-effort : a stock daemon answers "Failed" for any transaction it refuses, without reason,
+and "Not relayed" for one it keeps but doesn't relay, the causes are only in the node log
+*/
+type TxRejectedError struct {
+	Status string
+	Reason error
+}
+
+func (e *TxRejectedError) Error() string {
+	return "transaction rejected : " + e.Status
+}
+
+// Is, matches ErrTxRejected
+func (e *TxRejectedError) Is(target error) bool {
+	return target == ErrTxRejected
+}
+
+// Unwrap, the reason found in the status, nil when unknown
+func (e *TxRejectedError) Unwrap() error {
+	return e.Reason
+}
+
+// reasons of the detailed statuses answered by some nodes, lowercased phrases, in order
+var rejectionReasons = []struct {
+	phrase string
+	reason error
+}{
+	{"double spend", ErrDoubleSpend},
+	{"key image already spent", ErrDoubleSpend},
+	{"fee too low", ErrFeeTooLow},
+	{"fee is too low", ErrFeeTooLow},
+	{"fee is too small", ErrFeeTooLow},
+	{"transaction too big", ErrTxTooBig},
+	{"transaction is too big", ErrTxTooBig},
+	{"transaction too large", ErrTxTooBig},
+	{"transaction is too large", ErrTxTooBig},
+}
+
+// a sendrawtransaction status other than "OK" as a *TxRejectedError
+func rejection(status string) error {
+	rejected := &TxRejectedError{Status: status}
+	lower := strings.ToLower(strings.TrimSpace(status))
+	if lower == "not relayed" {
+		rejected.Reason = ErrTxNotRelayed
+		return rejected
+	}
+	for _, reason := range rejectionReasons {
+		if strings.Contains(lower, reason.phrase) {
+			rejected.Reason = reason.reason
+			break
+		}
+	}
+	return rejected
+}
+
+// response status sent along the results
+type statusResult struct {
+	Status string `json:"status"`
+}
+
+// returns ErrNodeBusy when the status of a result is "BUSY"
+func checkStatus(result []byte) error {
+	var status statusResult
+	// results without status, or which are not objects, are not checked
+	if json.Unmarshal(result, &status) != nil {
+		return nil
+	}
+	if status.Status == "BUSY" {
+		return ErrNodeBusy
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API errors tests
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// json_rpc handler echoing the request id with the given result or error
+func echoHandler(result string, rpcError string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if len(request.ID) == 0 {
+			request.ID = []byte("null")
+		}
+		if rpcError != "" {
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(request.ID) + `, "error": ` + rpcError + `}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(request.ID) + `, "result": ` + result + `}`))
+	}
+}
+
+func TestRPCError(t *testing.T) {
+	node, server := newHandlerNode(t, echoHandler("", `{"code": -32601, "message": "Method not found"}`))
+	defer server.Close()
+
+	_, err := node.BlockCount("withID")
+	var rpcError *RPCError
+	if !errors.As(err, &rpcError) || rpcError.Code != ErrorCodeMethodNotFound || rpcError.Message != "Method not found" {
+		t.Errorf("%swant *RPCError -32601, got %v", er, err)
+	}
+	if _, err := node.GetBlockCount(); !errors.As(err, &rpcError) {
+		t.Errorf("%sraw variant : want *RPCError, got %v", er, err)
+	}
+	t.Logf("%s%v", ok, err)
+}
+
+func TestHTTPError(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	_, err := node.Height()
+	var httpError *HTTPError
+	if !errors.As(err, &httpError) || httpError.StatusCode != http.StatusServiceUnavailable || string(httpError.Body) != "overloaded\n" {
+		t.Errorf("%swant *HTTPError 503, got %v", er, err)
+	}
+	t.Logf("%s%v", ok, err)
+}
+
+func TestErrEmptyBody(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	if _, err := node.Info(); !errors.Is(err, ErrEmptyBody) {
+		t.Errorf("%swant %v, got %v", er, ErrEmptyBody, err)
+	}
+}
+
+func TestErrIDMismatch(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": "other", "result": {"count": 357655, "status": "OK"}}`))
+	})
+	defer server.Close()
+
+	if _, err := node.BlockCount("withID"); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("%swant %v, got %v", er, ErrIDMismatch, err)
+	}
+	if _, err := node.GetBlockCount("withID"); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("%sraw variant : want %v, got %v", er, ErrIDMismatch, err)
+	}
+}
+
+func TestErrNodeBusy(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/getheight" {
+			w.Write([]byte(`{"status": "BUSY"}`))
+			return
+		}
+		echoHandler(`{"status": "BUSY"}`, "")(w, r)
+	})
+	defer server.Close()
+
+	if _, err := node.Height(); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%sGET : want %v, got %v", er, ErrNodeBusy, err)
+	}
+	if _, err := node.LastBlockHeader(); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%sPOST : want %v, got %v", er, ErrNodeBusy, err)
+	}
+
+	// core busy error code
+	busy, busyServer := newHandlerNode(t, echoHandler("", `{"code": -9, "message": "Core is busy"}`))
+	defer busyServer.Close()
+	if _, err := busy.LastBlockHeader(); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%scode -9 : want %v, got %v", er, ErrNodeBusy, err)
+	}
+}
@@ -0,0 +1,327 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium chain follower, blocks connected and disconnected on reorganizations
+
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultConfirmations, blocks on top of a block, itself included, before it is final
+	DefaultConfirmations = 10
+	// DefaultWindowSize, recent blocks kept by a follower to find the common ancestor of a reorganization
+	DefaultWindowSize = 100
+)
+
+// ErrReorgTooDeep, the node chain doesn't share any block of the follower window
+var ErrReorgTooDeep = errors.New("reorganization deeper than the follower window")
+
+// ErrNodeBehind, the node top block is under the follower one and out of its chain, Run waits for the node to catch up
+var ErrNodeBehind = errors.New("node is behind the follower")
+
+// ChainEventType, kind of chain event
+type ChainEventType int
+
+const (
+	// BlockConnected, a block is added on top of the followed chain
+	BlockConnected ChainEventType = iota
+	// BlockDisconnected, the top block is removed by a reorganization
+	BlockDisconnected
+	// BlockFinalized, a block reached the confirmations depth
+	BlockFinalized
+)
+
+func (eventType ChainEventType) String() string {
+	switch eventType {
+	case BlockConnected:
+		return "connected"
+	case BlockDisconnected:
+		return "disconnected"
+	case BlockFinalized:
+		return "finalized"
+	}
+	return "unknown event " + strconv.Itoa(int(eventType))
+}
+
+// ChainEntry, a block of the follower window
+type ChainEntry struct {
+	Height   uint32 `json:"height"`
+	Hash     string `json:"hash"`
+	PrevHash string `json:"prev_hash"`
+}
+
+// ChainEvent, a change of the followed chain, Header is only set for BlockConnected
+type ChainEvent struct {
+	Type ChainEventType
+	ChainEntry
+	Header *BlockHeader
+}
+
+// ChainCheckpoint, state of a follower, saved to resume following later
+type ChainCheckpoint struct {
+	Entries   []ChainEntry `json:"entries"`
+	NextFinal uint32       `json:"next_final"`
+}
+
+// FollowerConfig, settings of a chain follower
+type FollowerConfig struct {
+	// Confirmations, DefaultConfirmations when 0
+	Confirmations uint32
+	// WindowSize, DefaultWindowSize when 0, at least Confirmations
+	WindowSize int
+	// PollInterval, delay between two polls of Run, 10 seconds when 0
+	PollInterval time.Duration
+	// Checkpoint, resumes from a saved state, starts at the node top block when nil
+	Checkpoint *ChainCheckpoint
+}
+
+/*
+ChainFollower, follows the node chain and reports the blocks connected and disconnected on its top,
+a reorganization is found with the prev_hash of the new blocks and walked back to the common ancestor,
+a follower isn't safe for concurrent use, Checkpoint can be called from the events handler
+*/
+type ChainFollower struct {
+	node      *Iridiumd
+	config    FollowerConfig
+	window    []ChainEntry
+	nextFinal uint32
+}
+
+// NewChainFollower, returns a follower of the node chain
+func NewChainFollower(node *Iridiumd, config FollowerConfig) (*ChainFollower, error) {
+	if config.Confirmations == 0 {
+		config.Confirmations = DefaultConfirmations
+	}
+	if config.WindowSize == 0 {
+		config.WindowSize = DefaultWindowSize
+	}
+	if config.WindowSize < int(config.Confirmations) {
+		return nil, errors.New("window size must be at least " + strconv.FormatUint(uint64(config.Confirmations), 10))
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 10 * time.Second
+	}
+	follower := &ChainFollower{node: node, config: config}
+	if config.Checkpoint != nil {
+		entries := config.Checkpoint.Entries
+		for i := 1; i < len(entries); i++ {
+			if entries[i].Height != entries[i-1].Height+1 || entries[i].PrevHash != entries[i-1].Hash {
+				return nil, errors.New("checkpoint entries don't form a chain at height " + strconv.FormatUint(uint64(entries[i].Height), 10))
+			}
+		}
+		follower.window = append(follower.window, entries...)
+		follower.nextFinal = config.Checkpoint.NextFinal
+	}
+	return follower, nil
+}
+
+// Checkpoint, returns the follower state to resume from
+func (follower *ChainFollower) Checkpoint() ChainCheckpoint {
+	entries := make([]ChainEntry, len(follower.window))
+	copy(entries, follower.window)
+	return ChainCheckpoint{Entries: entries, NextFinal: follower.nextFinal}
+}
+
+// Tip, returns the top block of the followed chain, false before the first poll
+func (follower *ChainFollower) Tip() (ChainEntry, bool) {
+	if len(follower.window) == 0 {
+		return ChainEntry{}, false
+	}
+	return follower.window[len(follower.window)-1], true
+}
+
+/*
+Run, polls the node every PollInterval and sends the events to handle until ctx is done,
+retryable node errors and ErrNodeBehind wait for the next poll, other errors and handle errors stop Run
+*/
+func (follower *ChainFollower) Run(ctx context.Context, handle func(event ChainEvent) error) error {
+	ticker := time.NewTicker(follower.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		err := follower.Poll(ctx, handle)
+		if err != nil && ((!IsRetryable(err) && !errors.Is(err, ErrNodeBehind)) || ctx.Err() != nil) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Poll, brings the follower to the node top block and sends the events to handle,
+the follower state only moves once handle accepted an event, an error of handle stops Poll and is returned
+*/
+func (follower *ChainFollower) Poll(ctx context.Context, handle func(event ChainEvent) error) error {
+	tip, err := follower.node.LastBlockHeaderContext(ctx)
+	if err != nil {
+		return err
+	}
+	top, started := follower.Tip()
+	if !started {
+		follower.nextFinal = tip.Height
+		return follower.connect(tip, handle)
+	}
+	if top.Hash == tip.Hash {
+		return nil
+	}
+
+	// usual case, new blocks on top of ours
+	if tip.Height > top.Height {
+		header := tip
+		if tip.Height > top.Height+1 {
+			if header, err = follower.node.BlockHeaderByHeightContext(ctx, top.Height+1); err != nil {
+				return err
+			}
+		}
+		if header.PrevHash == top.Hash {
+			return follower.connectTo(ctx, tip, handle)
+		}
+	}
+
+	// reorganization, or the node is behind us
+	ancestor, err := follower.ancestor(ctx, tip)
+	if err != nil {
+		return err
+	}
+	if tip.Height <= top.Height && follower.window[ancestor].Hash == tip.Hash {
+		return nil
+	}
+	for len(follower.window)-1 > ancestor {
+		if err := follower.disconnect(handle); err != nil {
+			return err
+		}
+	}
+	return follower.connectTo(ctx, tip, handle)
+}
+
+/*
+index of the last window entry in the node chain, the hashes under tip are fetched in batches from the top of the window,
+no entry in the node chain is ErrNodeBehind when tip isn't above our top, ErrReorgTooDeep otherwise
+*/
+func (follower *ChainFollower) ancestor(ctx context.Context, tip *BlockHeader) (int, error) {
+	i := len(follower.window) - 1
+	for i >= 0 && follower.window[i].Height > tip.Height {
+		i--
+	}
+	if i >= 0 && follower.window[i].Height == tip.Height {
+		if follower.window[i].Hash == tip.Hash {
+			return i, nil
+		}
+		i--
+	}
+	for i >= 0 {
+		from := 0
+		if i >= blocksListPage {
+			from = i - blocksListPage + 1
+		}
+		headers, err := follower.node.blockHeaders(ctx, follower.window[from].Height, follower.window[i].Height)
+		if err != nil {
+			return 0, err
+		}
+		for ; i >= from; i-- {
+			if headers[i-from].Hash == follower.window[i].Hash {
+				return i, nil
+			}
+		}
+	}
+	if top, _ := follower.Tip(); tip.Height <= top.Height {
+		return 0, fmt.Errorf("%w : node at height %d, follower at %d", ErrNodeBehind, tip.Height, top.Height)
+	}
+	return 0, ErrReorgTooDeep
+}
+
+// connects the node blocks above our top up to tip, stops when the node chain changes meanwhile
+func (follower *ChainFollower) connectTo(ctx context.Context, tip *BlockHeader, handle func(event ChainEvent) error) error {
+	for {
+		top, _ := follower.Tip()
+		if top.Height >= tip.Height {
+			return nil
+		}
+		to := tip.Height
+		if to-top.Height > blocksListPage {
+			to = top.Height + blocksListPage
+		}
+		headers, err := follower.node.blockHeaders(ctx, top.Height+1, to)
+		if err != nil {
+			return err
+		}
+		for i := range headers {
+			if top, _ := follower.Tip(); headers[i].PrevHash != top.Hash {
+				return nil
+			}
+			if err := follower.connect(&headers[i], handle); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// block headers of heights from to to, in one batch
+func (node *Iridiumd) blockHeaders(ctx context.Context, from uint32, to uint32) ([]BlockHeader, error) {
+	headers := make([]BlockHeader, to-from+1)
+	batch := node.NewBatch()
+	for i := range headers {
+		batch.BlockHeaderByHeight(from+uint32(i), &headers[i])
+	}
+	if err := batch.Send(ctx); err != nil {
+		return nil, err
+	}
+	for _, call := range batch.calls {
+		if call.Error != nil {
+			return nil, call.Error
+		}
+	}
+	return headers, nil
+}
+
+func (follower *ChainFollower) connect(header *BlockHeader, handle func(event ChainEvent) error) error {
+	entry := ChainEntry{Height: header.Height, Hash: header.Hash, PrevHash: header.PrevHash}
+	if err := handle(ChainEvent{Type: BlockConnected, ChainEntry: entry, Header: header}); err != nil {
+		return err
+	}
+	follower.window = append(follower.window, entry)
+
+	// blocks reaching the confirmations depth
+	for _, entry := range follower.window {
+		if entry.Height < follower.nextFinal || header.Height-entry.Height+1 < follower.config.Confirmations {
+			continue
+		}
+		if err := handle(ChainEvent{Type: BlockFinalized, ChainEntry: entry}); err != nil {
+			return err
+		}
+		follower.nextFinal = entry.Height + 1
+	}
+
+	// older blocks leave the window, unless not final yet
+	for len(follower.window) > follower.config.WindowSize && follower.window[0].Height < follower.nextFinal {
+		follower.window = follower.window[1:]
+	}
+	return nil
+}
+
+func (follower *ChainFollower) disconnect(handle func(event ChainEvent) error) error {
+	top, _ := follower.Tip()
+	if err := handle(ChainEvent{Type: BlockDisconnected, ChainEntry: top}); err != nil {
+		return err
+	}
+	follower.window = follower.window[:len(follower.window)-1]
+	if top.Height < follower.nextFinal {
+		follower.nextFinal = top.Height
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium chain follower tests
+package iridiumdRPC
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fake chain answering getlastblockheader and getblockheaderbyheight, alone or in batches
+type testChain struct {
+	mu     sync.Mutex
+	hashes []string
+}
+
+// extends the chain with count blocks of branch, above height
+func (chain *testChain) grow(branch string, height int, count int) {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	chain.hashes = chain.hashes[:height+1]
+	for i := 0; i < count; i++ {
+		chain.hashes = append(chain.hashes, fmt.Sprintf("%s%08d", branch, len(chain.hashes)))
+	}
+}
+
+func (chain *testChain) answer(request testRequest) map[string]interface{} {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	height := len(chain.hashes) - 1
+	if request.Method == "getblockheaderbyheight" {
+		height = int(request.Params["height"].(float64))
+	}
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if height >= len(chain.hashes) {
+		response["error"] = map[string]interface{}{"code": ErrorCodeTooBigHeight, "message": "To big height"}
+		return response
+	}
+	prevHash := ""
+	if height > 0 {
+		prevHash = chain.hashes[height-1]
+	}
+	response["result"] = map[string]interface{}{
+		"block_header": map[string]interface{}{"height": height, "hash": chain.hashes[height], "prev_hash": prevHash},
+		"status":       "OK",
+	}
+	return response
+}
+
+func newTestChain(t *testing.T, length int) (*testChain, *Iridiumd, *httptest.Server) {
+	chain := &testChain{}
+	chain.grow("main", -1, length)
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			var requests []testRequest
+			json.Unmarshal(body, &requests)
+			responses := make([]map[string]interface{}, len(requests))
+			for i, request := range requests {
+				responses[i] = chain.answer(request)
+			}
+			json.NewEncoder(w).Encode(responses)
+			return
+		}
+		var request testRequest
+		json.Unmarshal(body, &request)
+		json.NewEncoder(w).Encode(chain.answer(request))
+	})
+	return chain, node, server
+}
+
+// polls and returns the events as "type height hash"
+func pollEvents(t *testing.T, follower *ChainFollower) []string {
+	var events []string
+	err := follower.Poll(context.Background(), func(event ChainEvent) error {
+		events = append(events, fmt.Sprintf("%s %d %s", event.Type, event.Height, event.Hash))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%sPoll : %v", er, err)
+	}
+	return events
+}
+
+func checkEvents(t *testing.T, events []string, want ...string) {
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("%sevents\n%q\nwant\n%q", er, events, want)
+	}
+}
+
+func TestChainFollower(t *testing.T) {
+	chain, node, server := newTestChain(t, 50)
+	defer server.Close()
+	follower, err := NewChainFollower(node, FollowerConfig{Confirmations: 3, WindowSize: 5})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	checkEvents(t, pollEvents(t, follower), "connected 49 main00000049")
+	checkEvents(t, pollEvents(t, follower))
+
+	chain.grow("main", 49, 2)
+	checkEvents(t, pollEvents(t, follower),
+		"connected 50 main00000050", "connected 51 main00000051", "finalized 49 main00000049")
+
+	// reorganization of 2 blocks, replaced by 3
+	chain.grow("alt", 49, 3)
+	checkEvents(t, pollEvents(t, follower),
+		"disconnected 51 main00000051", "disconnected 50 main00000050",
+		"connected 50 alt00000050", "connected 51 alt00000051", "connected 52 alt00000052",
+		"finalized 50 alt00000050")
+	t.Logf("%sreorganization followed", ok)
+
+	// many blocks at once, the window keeps the last ones
+	chain.grow("alt", 52, 40)
+	events := pollEvents(t, follower)
+	if len(events) != 80 || events[len(events)-1] != "finalized 90 alt00000090" {
+		t.Errorf("%s%d events, last %q", er, len(events), events[len(events)-1])
+	}
+	if checkpoint := follower.Checkpoint(); len(checkpoint.Entries) != 5 || checkpoint.NextFinal != 91 {
+		t.Errorf("%scheckpoint %+v", er, checkpoint)
+	}
+
+	// a node lagging on our chain, then on a short side branch forked under the window
+	chain.grow("alt", 90, 0)
+	checkEvents(t, pollEvents(t, follower))
+	chain.grow("side", 80, 5)
+	err = follower.Poll(context.Background(), func(ChainEvent) error { return nil })
+	if !errors.Is(err, ErrNodeBehind) {
+		t.Errorf("%swant %v, got %v", er, ErrNodeBehind, err)
+	}
+	if top, _ := follower.Tip(); top.Hash != "alt00000092" {
+		t.Errorf("%sfollower moved to %+v behind the node", er, top)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	behind := *follower
+	behind.config.PollInterval = time.Millisecond
+	if err := behind.Run(ctx, func(ChainEvent) error { return nil }); err != context.DeadlineExceeded {
+		t.Errorf("%sRun stopped on a node behind : %v", er, err)
+	}
+	t.Logf("%snode behind : %v", ok, err)
+
+	// a reorganization deeper than the window
+	chain.grow("deep", 80, 20)
+	if err := follower.Poll(context.Background(), func(ChainEvent) error { return nil }); !errors.Is(err, ErrReorgTooDeep) {
+		t.Errorf("%swant %v, got %v", er, ErrReorgTooDeep, err)
+	}
+}
+
+func TestChainFollower_Checkpoint(t *testing.T) {
+	chain, node, server := newTestChain(t, 20)
+	defer server.Close()
+	follower, err := NewChainFollower(node, FollowerConfig{Confirmations: 2})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	pollEvents(t, follower)
+
+	chain.grow("main", 19, 2)
+	checkEvents(t, pollEvents(t, follower),
+		"connected 20 main00000020", "finalized 19 main00000019", "connected 21 main00000021", "finalized 20 main00000020")
+
+	// the handler fails, the block will be sent again
+	chain.grow("main", 21, 1)
+	failure := errors.New("handler failure")
+	if err := follower.Poll(context.Background(), func(ChainEvent) error { return failure }); err != failure {
+		t.Fatalf("%swant %v, got %v", er, failure, err)
+	}
+	checkpoint := follower.Checkpoint()
+
+	// resumed after a reorganization of the checkpoint top block
+	chain.grow("alt", 20, 3)
+	resumed, err := NewChainFollower(node, FollowerConfig{Confirmations: 2, Checkpoint: &checkpoint})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	checkEvents(t, pollEvents(t, resumed),
+		"disconnected 21 main00000021",
+		"connected 21 alt00000021", "connected 22 alt00000022", "finalized 21 alt00000021",
+		"connected 23 alt00000023", "finalized 22 alt00000022")
+	t.Logf("%sresumed from checkpoint %+v", ok, checkpoint)
+}
+
+func TestChainFollower_DeepReorganization(t *testing.T) {
+	chain, node, server := newTestChain(t, 60)
+	defer server.Close()
+	follower, err := NewChainFollower(node, FollowerConfig{Confirmations: 60, WindowSize: 60})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	pollEvents(t, follower)
+	chain.grow("main", 59, 50)
+	pollEvents(t, follower)
+
+	// the common ancestor is more than a page of headers under the top
+	chain.grow("alt", 60, 50)
+	var disconnected, connected int
+	err = follower.Poll(context.Background(), func(event ChainEvent) error {
+		switch event.Type {
+		case BlockDisconnected:
+			disconnected++
+		case BlockConnected:
+			connected++
+		}
+		return nil
+	})
+	if err != nil || disconnected != 49 || connected != 50 {
+		t.Errorf("%s%d disconnected, %d connected, %v", er, disconnected, connected, err)
+	}
+	if top, _ := follower.Tip(); top.Hash != "alt00000110" {
+		t.Errorf("%stop %+v", er, top)
+	}
+	t.Logf("%s%d blocks disconnected", ok, disconnected)
+}
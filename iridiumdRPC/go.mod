@@ -1,3 +1,3 @@
 module github.com/steevebrush/iridium-go/iridiumdRPC
 
-go 1.12
+go 1.13
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC request ids
+
+package iridiumdRPC
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// IDGenerator, returns a new id for each json_rpc request, ids must be unique
+type IDGenerator func() string
+
+// CounterIDs, ids from a monotonic counter : prefix1, prefix2...
+func CounterIDs(prefix string) IDGenerator {
+	var counter uint64
+	return func() string {
+		return prefix + strconv.FormatUint(atomic.AddUint64(&counter, 1), 10)
+	}
+}
+
+// UUIDIDs, random version 4 UUIDs
+func UUIDIDs() IDGenerator {
+	return func() string {
+		var uuid [16]byte
+		if _, err := rand.Read(uuid[:]); err != nil {
+			panic("iridiumdRPC: no random source for UUID : " + err.Error())
+		}
+		uuid[6] = uuid[6]&0x0f | 0x40
+		uuid[8] = uuid[8]&0x3f | 0x80
+		id := hex.EncodeToString(uuid[:])
+		return id[:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:]
+	}
+}
+
+// ids of nodes without a configured generator
+var defaultIDs = CounterIDs("")
+
+// RequestHook, called before each json_rpc request with the call context, the method and the request id
+type RequestHook func(ctx context.Context, method string, id string)
+
+// WithIDGenerator, generates the json_rpc request ids with generator, a counter is used by default
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(config *clientConfig) error {
+		if generator == nil {
+			return errors.New("id generator is nil")
+		}
+		config.ids = generator
+		return nil
+	}
+}
+
+// WithRequestHook, calls hook before each json_rpc request, to match node logs with the caller traces
+func WithRequestHook(hook RequestHook) Option {
+	return func(config *clientConfig) error {
+		config.hook = hook
+		return nil
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID, returns a context sending the calls made with it with the given id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// returns the id of the next request : the context one or a generated one
+func (node *Iridiumd) nextID(ctx context.Context) string {
+	if id, found := ctx.Value(requestIDKey{}).(string); found {
+		return id
+	}
+	return node.generateID()
+}
+
+// returns a generated id
+func (node *Iridiumd) generateID() string {
+	if node.config == nil || node.config.ids == nil {
+		return defaultIDs()
+	}
+	return node.config.ids()
+}
+
+// calls the request hook, if any
+func (node *Iridiumd) notifyRequest(ctx context.Context, method string, id string) {
+	if node.config != nil && node.config.hook != nil {
+		node.config.hook(ctx, method, id)
+	}
+}
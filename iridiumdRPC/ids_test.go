@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC request ids tests
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestCounterIDs(t *testing.T) {
+	ids := CounterIDs("req-")
+	if first, second := ids(), ids(); first != "req-1" || second != "req-2" {
+		t.Errorf("%scounter ids : %s, %s", er, first, second)
+	}
+}
+
+func TestUUIDIDs(t *testing.T) {
+	ids := UUIDIDs()
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := ids(), ids()
+	if !uuid.MatchString(first) || first == second {
+		t.Errorf("%suuid ids : %s, %s", er, first, second)
+	}
+	t.Logf("%suuid id : %s", ok, first)
+}
+
+func TestIridiumd_RequestIDs(t *testing.T) {
+	server := httptest.NewServer(echoHandler(`{"count": 357655, "status": "OK"}`, ""))
+	defer server.Close()
+
+	var hooked []string
+	node, err := NewIridiumd(server.URL,
+		WithIDGenerator(CounterIDs("trace-")),
+		WithRequestHook(func(ctx context.Context, method string, id string) {
+			hooked = append(hooked, method+" "+id)
+		}))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	// generated, explicit and context ids
+	if _, err := node.BlockCount(); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if _, err := node.BlockCount("withID"); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if _, err := node.BlockCountContext(WithRequestID(context.Background(), "from-context")); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	want := []string{"getblockcount trace-1", "getblockcount withID", "getblockcount from-context"}
+	if len(hooked) != len(want) {
+		t.Fatalf("%shook called with %v, want %v", er, hooked, want)
+	}
+	for i := range want {
+		if hooked[i] != want[i] {
+			t.Errorf("%shook called with %q, want %q", er, hooked[i], want[i])
+		}
+	}
+}
+
+func TestIridiumd_RequestIDsChecked(t *testing.T) {
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc": "2.0", "result": {"count": 357655, "status": "OK"}}`))
+	})
+	defer server.Close()
+
+	// calls without explicit id are checked as well
+	if _, err := node.BlockCount(); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("%swant %v, got %v", er, ErrIDMismatch, err)
+	}
+}
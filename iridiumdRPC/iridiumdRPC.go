@@ -9,12 +9,11 @@ package iridiumdRPC
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strconv"
-	"time"
 )
 
 // Version, returns version major, minor and patch
@@ -24,347 +23,453 @@ func Version() (name string, major int, minor int, patch int) {
 }
 
 // node json/rpc api address, port and minimum version needed
+// nodes declared as struct literals share a pooled http client, use NewIridiumd to configure the transport
 type Iridiumd struct {
 	Address string
 	Port    int
+
+	config *clientConfig
+	// set once the node rejected a batch request
+	batchRejected int32
+	// requests are dispatched to the pool nodes when set
+	pool *NodePool
 }
 
-// Perform server request
-func doRequest(req *http.Request) (*http.Response, error) {
-	// use custom client : default timeout is "no timeout", this mean unlimited...
-	netClient := &http.Client{
-		Timeout: time.Second * 30,
-	}
-	resp, err := netClient.Do(req)
+// Perform server request, the request is aborted when its context is done
+func (node *Iridiumd) doRequest(req *http.Request) (*http.Response, error) {
+	resp, err := node.client().Do(req)
 	if err != nil {
+		// a cancelled or expired context is reported as is
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, err
 	}
 	return resp, nil
 }
 
-// Check server response
-func handleServerResponse(resp *http.Response) (interface{}, error) {
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("Server response : " + resp.Status)
-	}
+// Check server response, returns the raw body
+func handleServerResponse(resp *http.Response) ([]byte, error) {
+	// always close the body so that the connection goes back to the pool
 	defer resp.Body.Close()
 	// handle responses, errors
 	body, err := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	if len(body) == 0 {
-		return nil, errors.New("body is empty")
+		return nil, ErrEmptyBody
 	}
-	var mapBody interface{}
-	if err = json.Unmarshal(body, &mapBody); err != nil {
+	return body, nil
+}
+
+// decode a server response body into a generic map, raw methods output
+// numbers are float64 as in the previous versions, the typed methods and Amount decode them exactly
+func decodeRaw(body []byte) (map[string]interface{}, error) {
+	var mapBody map[string]interface{}
+	if err := json.Unmarshal(body, &mapBody); err != nil {
 		return nil, err
 	}
 	return mapBody, nil
 }
 
-func (node *Iridiumd) makeGetRequest(method string, params map[string]interface{}) (interface{}, error) {
-	// json parameters to send
-	var jsonPayload []byte
-	if params != nil {
-		var err error
-		params["jsonrpc"] = "2.0"
-		jsonPayload, err = json.Marshal(params)
-		if err != nil {
-			return nil, err
-		}
+/*
+construct and perform a request to a node endpoint, returns the checked response body
+idempotent requests of a pool may be sent to several nodes, the others to one node only
+*/
+func (node *Iridiumd) send(ctx context.Context, httpMethod string, path string, jsonPayload []byte, idempotent bool) ([]byte, error) {
+	if node.pool != nil {
+		return node.pool.send(ctx, httpMethod, path, jsonPayload, idempotent)
 	}
 
 	// construct request
-	req, err := http.NewRequest("GET", "http://"+node.Address+":"+strconv.Itoa(node.Port)+"/"+method, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequest(httpMethod, node.endpoint(path), bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
 
 	// perform request
-	resp, err := doRequest(req)
+	resp, err := node.doRequest(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 
 	// Handle server response
-	body, err := handleServerResponse(resp)
+	return handleServerResponse(resp)
+}
+
+func (node *Iridiumd) makeGetRequest(ctx context.Context, method string, params map[string]interface{}) ([]byte, error) {
+	// json parameters to send
+	if params != nil {
+		params["jsonrpc"] = "2.0"
+		return node.getRequest(ctx, method, params)
+	}
+	return node.getRequest(ctx, method, nil)
+}
+
+// request to a plain JSON endpoint, params are sent as JSON when not nil
+func (node *Iridiumd) getRequest(ctx context.Context, path string, params interface{}) ([]byte, error) {
+	var jsonPayload []byte
+	if params != nil {
+		var err error
+		jsonPayload, err = json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var body []byte
+	err := node.withRetry(ctx, IsIdempotent(path), func() error {
+		var err error
+		if body, err = node.send(ctx, "GET", path, jsonPayload, IsIdempotent(path)); err != nil {
+			return err
+		}
+		return checkStatus(body)
+	})
 	if err != nil {
 		return nil, err
 	}
-
 	return body, nil
 }
 
-func (node *Iridiumd) makePostRequest(method string, params map[string]interface{}) (interface{}, error) {
+func (node *Iridiumd) makePostRequest(ctx context.Context, method string, params map[string]interface{}) ([]byte, error) {
+	// check if an id exists and use it
+	id, _ := params["id"].(string)
+	delete(params, "id")
+	return node.postRequest(ctx, method, params, id)
+}
+
+// json_rpc request, returns the body once the envelope is checked, an empty id is generated
+func (node *Iridiumd) postRequest(ctx context.Context, method string, params interface{}, id string) ([]byte, error) {
+	if id == "" {
+		id = node.nextID(ctx)
+	}
+	node.notifyRequest(ctx, method, id)
+	return node.postNotified(ctx, method, params, id)
+}
+
+// json_rpc request whose id was already given to the request hook
+func (node *Iridiumd) postNotified(ctx context.Context, method string, params interface{}, id string) ([]byte, error) {
 	// json parameters
 	payload := make(map[string]interface{})
 	payload["jsonrpc"] = "2.0"
 	payload["method"] = method
 	payload["params"] = params
-
-	// check if an id exists and use it
-	if idValue, exist := params["id"]; exist {
-		payload["id"] = idValue
-		delete(params, "id")
+	if params == nil {
+		payload["params"] = struct{}{}
 	}
+	payload["id"] = id
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	// construct request
-	req, err := http.NewRequest("POST", "http://"+node.Address+":"+strconv.Itoa(node.Port)+"/json_rpc", bytes.NewBuffer(jsonPayload))
+	var body []byte
+	err = node.withRetry(ctx, IsIdempotent(method), func() error {
+		if body, err = node.send(ctx, "POST", "json_rpc", jsonPayload, IsIdempotent(method)); err != nil {
+			return err
+		}
+		var envelope rpcResponse
+		if err = json.Unmarshal(body, &envelope); err != nil {
+			return err
+		}
+		return checkEnvelope(&envelope, id)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return body, nil
+}
 
-	// perform request
-	resp, err := doRequest(req)
-	if err != nil {
-		return nil, err
+// check a json_rpc response envelope of the request sent with id
+func checkEnvelope(envelope *rpcResponse, id string) error {
+	if envelope.Error != nil {
+		return envelope.Error
 	}
 
-	// Handle server response
-	body, err := handleServerResponse(resp)
+	// check if req(id) and resp(id match)
+	if id != envelope.ID {
+		return fmt.Errorf("%w : sent %v, received %v", ErrIDMismatch, id, envelope.ID)
+	}
+
+	if len(envelope.Result) == 0 {
+		return ErrEmptyResult
+	}
+	return checkStatus(envelope.Result)
+}
+
+// GET request decoded into result
+func (node *Iridiumd) getResult(ctx context.Context, method string, params map[string]interface{}, result interface{}) error {
+	body, err := node.makeGetRequest(ctx, method, params)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return json.Unmarshal(body, result)
+}
 
-	// check if req(id) and resp(id match)
-	if payload["id"] != body.(map[string]interface{})["id"] {
-		return body, errors.New("Warning : ids doesn't match ")
+// json_rpc request, the "result" member of the envelope is decoded into result
+func (node *Iridiumd) postResult(ctx context.Context, method string, params map[string]interface{}, result interface{}) error {
+	body, err := node.makePostRequest(ctx, method, params)
+	if err != nil {
+		return err
 	}
+	// the envelope is already checked by makePostRequest
+	var envelope rpcResponse
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
 
-	return body, nil
+// returns the json_rpc params with the optional id
+func idParams(id []string) map[string]interface{} {
+	payload := make(map[string]interface{})
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	return payload
 }
 
 // node GET methods
 
 /*
 /getheight, returns current node height, current network height and status
-output example : map[height:357588 network_height:357588 status:OK]
 */
-func (node *Iridiumd) GetHeight() (map[string]interface{}, error) {
-	resp, err := node.makeGetRequest("getheight", nil)
-	if err != nil {
+func (node *Iridiumd) Height() (*NodeHeight, error) {
+	return node.HeightContext(context.Background())
+}
+
+// HeightContext, same as Height, the context cancels the request
+func (node *Iridiumd) HeightContext(ctx context.Context) (*NodeHeight, error) {
+	var height NodeHeight
+	if err := node.getResult(ctx, "getheight", nil, &height); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &height, nil
 }
 
 /*
 /getinfo, returns global node informations
-output example : map[alt_blocks_count:0 difficulty:2e+09 grey_peerlist_size:1480 height:357588 incoming_connections_count:14 last_known_block_index:357587 outgoing_connections_count:8 status:OK synced:true tx_count:532754 tx_pool_size:26 version:5.0.0 (0f3cc89) Release white_peerlist_size:108]
 */
-func (node *Iridiumd) GetInfo() (map[string]interface{}, error) {
-	resp, err := node.makeGetRequest("getinfo", nil)
-	if err != nil {
+func (node *Iridiumd) Info() (*NodeInfo, error) {
+	return node.InfoContext(context.Background())
+}
+
+// InfoContext, same as Info, the context cancels the request
+func (node *Iridiumd) InfoContext(ctx context.Context) (*NodeInfo, error) {
+	var info NodeInfo
+	if err := node.getResult(ctx, "getinfo", nil, &info); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &info, nil
 }
 
 /*
-/gettransactions, returns found or not tx and node status
+/gettransactions, returns found transactions as hex blobs and missed ones
 input params : txs_hashes []string
-output example: map[missed_tx:[] status:OK txs_as_hex:[]
 */
-func (node *Iridiumd) GetTransactions(txsHashes []string) (map[string]interface{}, error) {
+func (node *Iridiumd) Transactions(txsHashes []string) (*Transactions, error) {
+	return node.TransactionsContext(context.Background(), txsHashes)
+}
+
+// TransactionsContext, same as Transactions, the context cancels the request
+func (node *Iridiumd) TransactionsContext(ctx context.Context, txsHashes []string) (*Transactions, error) {
 	payload := make(map[string]interface{})
 	payload["txs_hashes"] = txsHashes
-	resp, err := node.makeGetRequest("gettransactions", payload)
-	if err != nil {
+	var txs Transactions
+	if err := node.getResult(ctx, "gettransactions", payload, &txs); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &txs, nil
 }
 
 /*
-/get_generated_coins, returns status and current circulating coins * denomination unit : 100000000
-output example :  map[alreadyGeneratedCoins:1860645432419705 status:OK]
-this mean : 1860645432419705/100000000 = 18 606 454,3241971 IRD
+/get_generated_coins, returns current circulating coins in atomic units (denomination unit : 100000000)
 */
-func (node *Iridiumd) GetGeneratedCoins() (map[string]interface{}, error) {
-	resp, err := node.makeGetRequest("get_generated_coins", nil)
-	if err != nil {
-		return nil, err
+func (node *Iridiumd) GeneratedCoins() (Amount, error) {
+	return node.GeneratedCoinsContext(context.Background())
+}
+
+// GeneratedCoinsContext, same as GeneratedCoins, the context cancels the request
+func (node *Iridiumd) GeneratedCoinsContext(ctx context.Context) (Amount, error) {
+	var coins GeneratedCoins
+	if err := node.getResult(ctx, "get_generated_coins", nil, &coins); err != nil {
+		return 0, err
 	}
-	return resp.(map[string]interface{}), nil
+	return coins.AlreadyGeneratedCoins, nil
 }
 
 // POST methods
 
 /*
 getblockcount, returns current height (including current mined block),
-id is optional but when specified, request id and response id are compared
-output :  map[jsonrpc:2.0 result:map[count:357655 status:OK]]
-or with id : map[id:withID jsonrpc:2.0 result:map[count:357655 status:OK]]
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 */
-func (node *Iridiumd) GetBlockCount(id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("getblockcount", payload)
-	if err != nil {
-		return nil, err
+func (node *Iridiumd) BlockCount(id ...string) (uint32, error) {
+	return node.BlockCountContext(context.Background(), id...)
+}
+
+// BlockCountContext, same as BlockCount, the context cancels the request
+func (node *Iridiumd) BlockCountContext(ctx context.Context, id ...string) (uint32, error) {
+	var result blockCountResult
+	if err := node.postResult(ctx, "getblockcount", idParams(id), &result); err != nil {
+		return 0, err
 	}
-	return resp.(map[string]interface{}), nil
+	return result.Count, nil
 }
 
 /*
 getcurrencyid, returns genesis block hash
-id is optional but when specified, request id and response id are compared
-output : map[jsonrpc:2.0 result:map[currency_id_blob:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43]]
-or with id : map[id:withID jsonrpc:2.0 result:map[currency_id_blob:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43]]
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 */
-func (node *Iridiumd) GetCurrencyid(id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("getcurrencyid", payload)
-	if err != nil {
-		return nil, err
+func (node *Iridiumd) CurrencyID(id ...string) (string, error) {
+	return node.CurrencyIDContext(context.Background(), id...)
+}
+
+// CurrencyIDContext, same as CurrencyID, the context cancels the request
+func (node *Iridiumd) CurrencyIDContext(ctx context.Context, id ...string) (string, error) {
+	var result currencyIDResult
+	if err := node.postResult(ctx, "getcurrencyid", idParams(id), &result); err != nil {
+		return "", err
 	}
-	return resp.(map[string]interface{}), nil
+	return result.CurrencyIDBlob, nil
 }
 
 /*
 getlastblockheader, last mined block header
-id is optional but when specified, request id and response id are compared
-output :  map[jsonrpc:2.0 result:map[block_header:map[depth:0 difficulty:1.75e+09 hash:982addbdf1dc687e886baffcbdf6c29fabab8269787980991ee1c34242433cdd height:357765 major_version:5 minor_version:0 nonce:31207 orphan_status:false prev_hash:3b7ca9fdf86ad62a7411827299b4028e5d2701cfa3ccae1a3390faebecfa5b42 reward:2.437467306e+09 timestamp:1.567538093e+09] status:OK]]
-or with id : map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:0 difficulty:1.75e+09 hash:982addbdf1dc687e886baffcbdf6c29fabab8269787980991ee1c34242433cdd height:357765 major_version:5 minor_version:0 nonce:31207 orphan_status:false prev_hash:3b7ca9fdf86ad62a7411827299b4028e5d2701cfa3ccae1a3390faebecfa5b42 reward:2.437467306e+09 timestamp:1.567538093e+09] status:OK]]
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 */
-func (node *Iridiumd) GetLastBlockheader(id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("getlastblockheader", payload)
-	if err != nil {
+func (node *Iridiumd) LastBlockHeader(id ...string) (*BlockHeader, error) {
+	return node.LastBlockHeaderContext(context.Background(), id...)
+}
+
+// LastBlockHeaderContext, same as LastBlockHeader, the context cancels the request
+func (node *Iridiumd) LastBlockHeaderContext(ctx context.Context, id ...string) (*BlockHeader, error) {
+	var result blockHeaderResult
+	if err := node.postResult(ctx, "getlastblockheader", idParams(id), &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &result.BlockHeader, nil
 }
 
 /*
 getblockheaderbyhash, returns the block header by hash
-id is optional but when specified, request id and response id are compared
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 input : hash string
-output : map[jsonrpc:2.0 result:map[block_header:map[depth:357767 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9.536743164e+09 timestamp:0] status:OK]]
-or with id :  map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:357767 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9.536743164e+09 timestamp:0] status:OK]]
 */
-func (node *Iridiumd) GetBlockHeaderByHash(hash string, id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
+func (node *Iridiumd) BlockHeaderByHash(hash string, id ...string) (*BlockHeader, error) {
+	return node.BlockHeaderByHashContext(context.Background(), hash, id...)
+}
+
+// BlockHeaderByHashContext, same as BlockHeaderByHash, the context cancels the request
+func (node *Iridiumd) BlockHeaderByHashContext(ctx context.Context, hash string, id ...string) (*BlockHeader, error) {
+	payload := idParams(id)
 	payload["hash"] = hash
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("getblockheaderbyhash", payload)
-	if err != nil {
+	var result blockHeaderResult
+	if err := node.postResult(ctx, "getblockheaderbyhash", payload, &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &result.BlockHeader, nil
 }
 
 /*
 getblockheaderbyheight, returns the block header at desired height
-id is optional but when specified, request id and response id are compared
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 input : height uint32
-output : map[jsonrpc:2.0 result:map[block_header:map[depth:357672 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9.536743164e+09 timestamp:0] status:OK]]
-or with id : map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:357572 difficulty:10857 hash:823f7bf3e6ccf9818c7b58aebebde7bf79f25b5118dc02233ac38333340ed894 height:100 major_version:1 minor_version:0 nonce:4.29672923e+08 orphan_status:false prev_hash:3f726a8f697c1cc03f54bf0f1d609ef677b7b8597f24a67aa733bd8a810f023c reward:9.533105872e+09 timestamp:1.504560271e+09] status:OK]]
 */
-func (node *Iridiumd) GetBlockHeaderByHeight(height uint32, id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
+func (node *Iridiumd) BlockHeaderByHeight(height uint32, id ...string) (*BlockHeader, error) {
+	return node.BlockHeaderByHeightContext(context.Background(), height, id...)
+}
+
+// BlockHeaderByHeightContext, same as BlockHeaderByHeight, the context cancels the request
+func (node *Iridiumd) BlockHeaderByHeightContext(ctx context.Context, height uint32, id ...string) (*BlockHeader, error) {
+	payload := idParams(id)
 	payload["height"] = height
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("getblockheaderbyheight", payload)
-	if err != nil {
+	var result blockHeaderResult
+	if err := node.postResult(ctx, "getblockheaderbyheight", payload, &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &result.BlockHeader, nil
 }
 
 /*
 f_blocks_list_json, returns 30 blocks headers from desired height to height - 30
-id is optional but when specified, request id and response id are compared
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 input : height uint32
-output : map[jsonrpc:2.0 result:map[blocks:[map[cumul_size:410 difficulty:9997 hash:f8efb98beee5930a403b16a12bb212f88a06c84dadb330090eb0e22528b3c90f height:30 reward:9.53565183e+09 timestamp:1.504551188e+09 tx_count:1], etc...
-or with id : map[id:withID jsonrpc:2.0 result:map[blocks:[map[cumul_size:408 difficulty:10857 hash:823f7bf3e6ccf9818c7b58aebebde7bf79f25b5118dc02233ac38333340ed894 height:100 reward:9.533105872e+09 timestamp:1.504560271e+09 tx_count:1], etc...
 */
-func (node *Iridiumd) GetBlocksList(height uint32, id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
+func (node *Iridiumd) BlocksList(height uint32, id ...string) ([]BlockShort, error) {
+	return node.BlocksListContext(context.Background(), height, id...)
+}
+
+// BlocksListContext, same as BlocksList, the context cancels the request
+func (node *Iridiumd) BlocksListContext(ctx context.Context, height uint32, id ...string) ([]BlockShort, error) {
+	payload := idParams(id)
 	payload["height"] = height
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("f_blocks_list_json", payload)
-	if err != nil {
+	var result blocksListResult
+	if err := node.postResult(ctx, "f_blocks_list_json", payload, &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return result.Blocks, nil
 }
 
 /*
-f_block_json, returns block detail at  desired hash
-id is optional but when specified, request id and response id are compared
+f_block_json, returns block detail at desired hash
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 input : hash string
-output :  map[jsonrpc:2.0 result:map[block:map[alreadyGeneratedCoins:9536743164 alreadyGeneratedTransactions:1 baseReward:9.536743164e+09 blockSize:118 depth:357785 difficulty:1 effectiveSizeMedian:20000 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false penalty:0 prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9.536743164e+09 sizeMedian:0 timestamp:0 totalFeeAmount:0 transactions:[map[amount_out:9.536743164e+09 fee:0 hash:f3fe271b4edceebf60a29d535a8dec957809baf4c69549a09ae113eb88a5f1ad size:78]] transactionsCumulativeSize:78] status:OK]]
-or with id : same with [id:withID...
 */
-func (node *Iridiumd) GetBlockDetails(hash string, id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
+func (node *Iridiumd) BlockDetails(hash string, id ...string) (*BlockDetails, error) {
+	return node.BlockDetailsContext(context.Background(), hash, id...)
+}
+
+// BlockDetailsContext, same as BlockDetails, the context cancels the request
+func (node *Iridiumd) BlockDetailsContext(ctx context.Context, hash string, id ...string) (*BlockDetails, error) {
+	payload := idParams(id)
 	payload["hash"] = hash
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("f_block_json", payload)
-	if err != nil {
+	var result blockDetailsResult
+	if err := node.postResult(ctx, "f_block_json", payload, &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &result.Block, nil
 }
 
 /*
-f_transaction_json, returns block detail at  desired hash
-id is optional but when specified, request id and response id are compared
+f_transaction_json, returns transaction detail at desired hash and the block including it
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 input : hash string
-output :  map[jsonrpc:2.0 result:map[block:map[cumul_size:8430 difficulty:4.75677472e+08 hash:f0c002e703d8dc19f6a5ca844805015213f223f3a52a258d3fa22a69e8177213 height:357782 reward:3.85792735e+08 timestamp:1.567540598e+09 tx_count:7] status:OK tx:map[extra:01e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000 unlock_time:357802 version:1 vin:[map[type:ff value:map[height:357782]]] vout:[map[amount:241 target:map[data:map[key:5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58] type:02]] map[amount:9000 target:map[data:map[key:4b40a1f97a411881321022de6a8f532f30c4c404c39c345e26250e162935af79] type:02]] map[amount:600000 target:map[data:map[key:c7c6b07057750fff3ba1520ffbd71aeeb080f796315764ac2021f7f28d064b42] type:02]] map[amount:7e+06 target:map[data:map[key:638e1cc733f3566091bfbf696c680264a53dec7e75f82b5d09bd96b7e0e788d0] type:02]] map[amount:3e+07 target:map[data:map[key:fc0b8cb0e7bf0a7fd8bee0765add136a03b72ddca9c5bd83b755ac3900cc4822] type:02]] map[amount:4e+08 target:map[data:map[key:e9caba0c8f62238ebe81997faa69212c26a21d26199f2c2ba3aa125e3ec33c2c] type:02]] map[amount:2e+09 target:map[data:map[key:d1592617829f57545c0929e4cb286c019da0dd1d1910b7531a25a396466b0bda] type:02]]]] txDetails:map[amount_out:2.437609241e+09 fee:0 hash:ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2 mixin:0 paymentId: size:319]]]
-or with id : same with [id:withID...
 */
-func (node *Iridiumd) GetTransactionDetails(hash string, id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
+func (node *Iridiumd) TransactionDetails(hash string, id ...string) (*TransactionDetails, error) {
+	return node.TransactionDetailsContext(context.Background(), hash, id...)
+}
+
+// TransactionDetailsContext, same as TransactionDetails, the context cancels the request
+func (node *Iridiumd) TransactionDetailsContext(ctx context.Context, hash string, id ...string) (*TransactionDetails, error) {
+	payload := idParams(id)
 	payload["hash"] = hash
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("f_transaction_json", payload)
-	if err != nil {
+	var result TransactionDetails
+	if err := node.postResult(ctx, "f_transaction_json", payload, &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return &result, nil
 }
 
 /*
-f_on_transactions_pool_json, get mem pool status
-id is optional but when specified, request id and response id are compared
-output :  7c0902baf3e50f7f3a202bdafe5091a1eb6befb9eedd89b6cbfa2b051450a73a
-or with id : map[id:withID jsonrpc:2.0 ...
+f_on_transactions_pool_json, returns the transactions waiting in the mem pool
+id is optional, a unique id is generated otherwise, request id and response id are always compared
 */
-func (node *Iridiumd) GetTransactionsPool(id ...string) (map[string]interface{}, error) {
-	payload := make(map[string]interface{})
-	if len(id) != 0 {
-		payload["id"] = id[0]
-	}
-	resp, err := node.makePostRequest("f_on_transactions_pool_json", payload)
-	if err != nil {
+func (node *Iridiumd) TransactionsPool(id ...string) ([]PoolTransaction, error) {
+	return node.TransactionsPoolContext(context.Background(), id...)
+}
+
+// TransactionsPoolContext, same as TransactionsPool, the context cancels the request
+func (node *Iridiumd) TransactionsPoolContext(ctx context.Context, id ...string) ([]PoolTransaction, error) {
+	var result transactionsPoolResult
+	if err := node.postResult(ctx, "f_on_transactions_pool_json", idParams(id), &result); err != nil {
 		return nil, err
 	}
-	return resp.(map[string]interface{}), nil
+	return result.Transactions, nil
 }
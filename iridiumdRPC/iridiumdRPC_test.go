@@ -7,20 +7,45 @@
 package iridiumdRPC
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net"
+	"os"
 	"strconv"
 	"testing"
+	"time"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
 )
 
 // Colorize output...
 const ok = "\033[32m[OK] : \033[0m"
 const er = "\033[31m[ERROR] : \033[0m"
 
-// iridium node address for tests
-var node = Iridiumd{
-	address: "127.0.0.1",
-	port:    13007,
+// fake iridium node for tests, and its address
+var daemon *iridiumdtest.Server
+var node Iridiumd
+
+// transactions known by the fake node
+const (
+	minedTx   = "ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2"
+	pendingTx = "d56f9b6e3257568151de667b679c5fc3c03b02ac6ce9a28d346e6c0f6beafd5c"
+)
+
+// starts the fake node with 120 blocks, a mined and a pending transaction
+func TestMain(m *testing.M) {
+	daemon = iridiumdtest.NewServer()
+	daemon.AddToPool(iridiumdtest.Transaction{Hash: minedTx, Fee: 100000, AmountOut: 500000000, Size: 400, Hex: "01"})
+	daemon.Mine(120)
+	daemon.AddToPool(iridiumdtest.Transaction{Hash: pendingTx, Fee: 100000, AmountOut: 200000000, Size: 400})
+	node = Iridiumd{
+		Address: daemon.Address(),
+		Port:    daemon.Port(),
+	}
+	code := m.Run()
+	daemon.Close()
+	os.Exit(code)
 }
 
 // test the returning version
@@ -34,241 +59,323 @@ func TestVersion(t *testing.T) {
 func TestValidateAddress(t *testing.T) {
 	// constructor test
 	node1 := Iridiumd{
-		address: "127.0.0.1",
-		port:    13007,
+		Address: "127.0.0.1",
+		Port:    13007,
 	}
 
 	node2 := Iridiumd{
-		address: "127.0.0.1",
-		port:    13007,
+		Address: "127.0.0.1",
+		Port:    13007,
 	}
 
 	node3 := Iridiumd{
-		address: "nodes.ird.cash",
-		port:    13007,
+		Address: "localhost",
+		Port:    13007,
 	}
 
 	//this one shouldn't resolve
 	node4 := Iridiumd{
-		address: "do.not.resolve",
-		port:    13007,
+		Address: "do.not.resolve.invalid",
+		Port:    13007,
 	}
 
 	// Validate constructor
 	if node1 != node2 {
-		t.Errorf("%sIridiumd struct error : want %s:%d, got %s:%d", er, node2.address, node2.port, node1.address, node1.port)
+		t.Errorf("%sIridiumd struct error : want %s:%d, got %s:%d", er, node2.Address, node2.Port, node1.Address, node1.Port)
 	} else {
 		t.Logf("%sIridiumd struct ok", ok)
 	}
 
 	// dns resolver ok
-	addr, err := net.ResolveIPAddr("ip", node3.address)
+	addr, err := net.ResolveIPAddr("ip", node3.Address)
 	if err != nil {
 		t.Errorf("%sResolution error : %s", er, err.Error())
 	}
-	t.Logf("%sResolved address %s is %s", ok, node3.address, addr.String())
+	t.Logf("%sResolved address %s is %s", ok, node3.Address, addr.String())
 
 	// dns resolver error
-	addr, err = net.ResolveIPAddr("ip", node4.address)
+	addr, err = net.ResolveIPAddr("ip", node4.Address)
 	if err != nil {
 		t.Logf("%sResolution error : %s, this is expected.", ok, err.Error())
 	}
 }
 
-func TestIridiumd_GetHeight(t *testing.T) {
-	resp, err := node.GetHeight()
+func TestIridiumd_Height(t *testing.T) {
+	resp, err := node.Height()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetHeight returns :\n%v", ok, resp)
+		t.Logf("%sHeight returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetInfo(t *testing.T) {
-	resp, err := node.GetInfo()
+func TestIridiumd_Info(t *testing.T) {
+	resp, err := node.Info()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetInfo returns :\n%v", ok, resp)
+		t.Logf("%sInfo returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetTransactions(t *testing.T) {
+func TestIridiumd_Transactions(t *testing.T) {
 	txArray := []string{
-		"d56f9b6e3257568151de667b679c5fc3c03b02ac6ce9a28d346e6c0f6beafd5c",
+		minedTx,
 		"d56f9b6e3257568151de667b679c5fc3c03b02ac6ce9a28d346e6c0f6beafd56"}
 
-	resp, err := node.GetTransactions(txArray)
+	resp, err := node.Transactions(txArray)
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetTransactions returns :\n%v", ok, resp)
+		t.Logf("%sTransactions returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetGeneratedCoins(t *testing.T) {
-	resp, err := node.GetGeneratedCoins()
+func TestIridiumd_GeneratedCoins(t *testing.T) {
+	resp, err := node.GeneratedCoins()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetGeneratedCoins returns :\n%v", ok, resp)
-		/*t.Logf("%sGetGeneratedCoins returns :\n%v",ok,printJson(resp, true) )*/
+		t.Logf("%sGeneratedCoins returns :\n%v", ok, resp)
+		/*t.Logf("%sGeneratedCoins returns :\n%v",ok,printJson(resp, true) )*/
 	}
 }
 
 // POST methods
 
-func TestIridiumd_GetBlockCount(t *testing.T) {
+func TestIridiumd_BlockCount(t *testing.T) {
 	// without id
-	resp, err := node.GetBlockCount()
+	resp, err := node.BlockCount()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockCount without id returns :\n%v", ok, resp)
+		t.Logf("%sBlockCount without id returns :\n%v", ok, resp)
 	}
 	// with an id
-	resp, err = node.GetBlockCount("withID")
+	resp, err = node.BlockCount("withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockCount with id returns :\n%v", ok, resp)
+		t.Logf("%sBlockCount with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetCurrencyid(t *testing.T) {
+func TestIridiumd_CurrencyID(t *testing.T) {
 	// without id
-	resp, err := node.GetCurrencyid()
+	resp, err := node.CurrencyID()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetCurrencyid without id returns :\n%v", ok, resp)
+		t.Logf("%sCurrencyID without id returns :\n%v", ok, resp)
 	}
 	// with an id
-	resp, err = node.GetCurrencyid("withID")
+	resp, err = node.CurrencyID("withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetCurrencyid with id returns :\n%v", ok, resp)
+		t.Logf("%sCurrencyID with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetLastBlockheader(t *testing.T) {
+func TestIridiumd_LastBlockHeader(t *testing.T) {
 	// without id
-	resp, err := node.GetLastBlockheader()
+	resp, err := node.LastBlockHeader()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetLastBlockheader without id returns :\n%v", ok, resp)
+		t.Logf("%sLastBlockHeader without id returns :\n%v", ok, resp)
 	}
 	// with an id
-	resp, err = node.GetLastBlockheader("withID")
+	resp, err = node.LastBlockHeader("withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetLastBlockheader with id returns :\n%v", ok, resp)
+		t.Logf("%sLastBlockHeader with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetBlockHeaderByHash(t *testing.T) {
+func TestIridiumd_BlockHeaderByHash(t *testing.T) {
 	// without id
-	resp, err := node.GetBlockHeaderByHash("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43")
+	resp, err := node.BlockHeaderByHash("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockHeaderByHash without id returns :\n%v", ok, resp)
+		t.Logf("%sBlockHeaderByHash without id returns :\n%v", ok, resp)
 	}
 	// with id
-	resp, err = node.GetBlockHeaderByHash("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43", "withID")
+	resp, err = node.BlockHeaderByHash("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43", "withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockHeaderByHash with id returns :\n%v", ok, resp)
+		t.Logf("%sBlockHeaderByHash with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetBlockHeaderByHeight(t *testing.T) {
+func TestIridiumd_BlockHeaderByHeight(t *testing.T) {
 	// without id (this is the genesis block ;-)
-	resp, err := node.GetBlockHeaderByHeight(0)
+	resp, err := node.BlockHeaderByHeight(0)
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockHeaderByHeight without id returns :\n%v", ok, resp)
+		t.Logf("%sBlockHeaderByHeight without id returns :\n%v", ok, resp)
 	}
 	// with id
-	resp, err = node.GetBlockHeaderByHeight(100, "withID")
+	resp, err = node.BlockHeaderByHeight(100, "withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockHeaderByHeight with id returns :\n%v", ok, resp)
+		t.Logf("%sBlockHeaderByHeight with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetBlocksList(t *testing.T) {
+func TestIridiumd_BlocksList(t *testing.T) {
 	// without id (this is the genesis block ;-)
-	resp, err := node.GetBlocksList(30)
+	resp, err := node.BlocksList(30)
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlocksList without id returns :\n%v", ok, resp)
+		t.Logf("%sBlocksList without id returns :\n%v", ok, resp)
 	}
 	// with id
-	resp, err = node.GetBlocksList(100, "withID")
+	resp, err = node.BlocksList(100, "withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlocksList with id returns :\n%v", ok, resp)
+		t.Logf("%sBlocksList with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetBlockDetails(t *testing.T) {
+func TestIridiumd_BlockDetails(t *testing.T) {
 	// without id
-	resp, err := node.GetBlockDetails("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43")
+	resp, err := node.BlockDetails("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockDetails without id returns :\n%v", ok, resp)
+		t.Logf("%sBlockDetails without id returns :\n%v", ok, resp)
 	}
 	// with id
-	resp, err = node.GetBlockDetails("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43", "withID")
+	resp, err = node.BlockDetails("9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43", "withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetBlockDetails with id returns :\n%v", ok, resp)
+		t.Logf("%sBlockDetails with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetTransactionDetails(t *testing.T) {
+func TestIridiumd_TransactionDetails(t *testing.T) {
 	// without id
-	resp, err := node.GetTransactionDetails("ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2")
+	resp, err := node.TransactionDetails(minedTx)
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetTransactionDetails without id returns :\n%v", ok, resp)
+		t.Logf("%sTransactionDetails without id returns :\n%v", ok, resp)
 	}
 	// with id
-	resp, err = node.GetTransactionDetails("ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2", "withID")
+	resp, err = node.TransactionDetails(minedTx, "withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetTransactionDetails with id returns :\n%v", ok, resp)
+		t.Logf("%sTransactionDetails with id returns :\n%v", ok, resp)
 	}
 }
 
-func TestIridiumd_GetTransactionsPool(t *testing.T) {
+func TestIridiumd_TransactionsPool(t *testing.T) {
 	// without id
-	resp, err := node.GetTransactionsPool()
+	resp, err := node.TransactionsPool()
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetTransactionsPool without id returns :\n%v", ok, resp)
+		t.Logf("%sTransactionsPool without id returns :\n%v", ok, resp)
 	}
 	// with an id
-	resp, err = node.GetTransactionsPool("withID")
+	resp, err = node.TransactionsPool("withID")
 	if err != nil {
 		t.Errorf("%s %s", er, err)
 	} else {
-		t.Logf("%sGetTransactionsPool with id returns :\n%v", ok, resp)
+		t.Logf("%sTransactionsPool with id returns :\n%v", ok, resp)
+	}
+}
+
+// the map methods of the previous versions keep their names and responses
+func TestIridiumd_Deprecated(t *testing.T) {
+	info, err := node.GetInfo()
+	if err != nil || info["status"] != "OK" {
+		t.Errorf("%sGetInfo returns %v, %v", er, info, err)
+	}
+	header, err := node.GetBlockHeaderByHeight(0, "withID")
+	if err != nil || header["id"] != "withID" {
+		t.Fatalf("%sGetBlockHeaderByHeight returns %v, %v", er, header, err)
+	}
+	result, _ := header["result"].(map[string]interface{})
+	blockHeader, _ := result["block_header"].(map[string]interface{})
+	if blockHeader["hash"] != iridiumdtest.GenesisHash {
+		t.Errorf("%sGetBlockHeaderByHeight returns %v", er, header)
+	}
+	t.Logf("%smap methods unchanged", ok)
+}
+
+// the fake node answers like a live one
+func TestIridiumd_FakeNode(t *testing.T) {
+	header, err := node.BlockHeaderByHeight(100)
+	if err != nil || header.Height != 100 || header.Depth != 20 {
+		t.Fatalf("%sBlockHeaderByHeight returns %+v, %v", er, header, err)
+	}
+	previous, err := node.BlockHeaderByHeight(99)
+	if err != nil || previous.Hash != header.PrevHash {
+		t.Errorf("%sblock 99 %+v doesn't link to block 100 %+v, %v", er, previous, header, err)
+	}
+	if currency, err := node.CurrencyID(); err != nil || currency != iridiumdtest.GenesisHash {
+		t.Errorf("%sCurrencyID returns %s, %v", er, currency, err)
+	}
+	if details, err := node.TransactionDetails(minedTx); err != nil || details.Block.Height != 1 {
+		t.Errorf("%sTransactionDetails returns %+v, %v", er, details, err)
+	}
+	if pool, err := node.TransactionsPool(); err != nil || len(pool) != 1 || pool[0].Hash != pendingTx {
+		t.Errorf("%sTransactionsPool returns %+v, %v", er, pool, err)
+	}
+	if _, err := node.BlockHeaderByHeight(1000); err == nil {
+		t.Errorf("%sno error for a too big height", er)
+	}
+	t.Logf("%sfake node chain at height %d", ok, daemon.Height())
+}
+
+func TestIridiumd_Faults(t *testing.T) {
+	defer daemon.ClearFaults()
+
+	daemon.Inject(iridiumdtest.Fault{Method: "getblockcount", Busy: true, Count: 1})
+	if _, err := node.BlockCount(); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%sbusy : want %v, got %v", er, ErrNodeBusy, err)
+	}
+	daemon.Inject(iridiumdtest.Fault{Method: "getinfo", Busy: true, Count: 1})
+	if _, err := node.Info(); !errors.Is(err, ErrNodeBusy) {
+		t.Errorf("%sbusy endpoint : want %v, got %v", er, ErrNodeBusy, err)
+	}
+	daemon.Inject(iridiumdtest.Fault{WrongID: true, Count: 1})
+	if _, err := node.LastBlockHeader(); !errors.Is(err, ErrIDMismatch) {
+		t.Errorf("%sid mismatch : want %v, got %v", er, ErrIDMismatch, err)
+	}
+	daemon.Inject(iridiumdtest.Fault{Malformed: true, Count: 1})
+	var syntaxError *json.SyntaxError
+	if _, err := node.LastBlockHeader(); !errors.As(err, &syntaxError) {
+		t.Errorf("%smalformed JSON : want *json.SyntaxError, got %v", er, err)
+	}
+	daemon.Inject(iridiumdtest.Fault{HTTPStatus: 500, Count: 1})
+	var httpError *HTTPError
+	if _, err := node.Height(); !errors.As(err, &httpError) || httpError.StatusCode != 500 {
+		t.Errorf("%shttp status : want *HTTPError, got %v", er, err)
+	}
+	daemon.Inject(iridiumdtest.Fault{Latency: time.Minute, Count: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := node.HeightContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("%slatency : want %v, got %v", er, context.DeadlineExceeded, err)
+	}
+
+	// faults are consumed
+	if _, err := node.LastBlockHeader(); err != nil {
+		t.Errorf("%s %s", er, err)
 	}
+	t.Logf("%sinjected faults reported", ok)
 }
 
 // returns a map[string] as json with or without indentation (indent bool parameter), mainly for debugging
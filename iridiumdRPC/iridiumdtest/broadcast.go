@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node transactions sent by /sendrawtransaction
+
+package iridiumdtest
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+const (
+	// MinimumFee, smallest fee accepted by /sendrawtransaction
+	MinimumFee = 100000
+	// MaxTransactionSize, largest transaction blob accepted by /sendrawtransaction
+	MaxTransactionSize = 100000
+)
+
+// key images spent by the known transactions with a Hex blob
+func (chain *chain) spentKeyImages() map[cryptonote.KeyImage]bool {
+	spent := make(map[cryptonote.KeyImage]bool)
+	add := func(transaction Transaction) {
+		blob, err := hex.DecodeString(transaction.Hex)
+		if err != nil {
+			return
+		}
+		if decoded, err := cryptonote.DecodeTransaction(blob); err == nil {
+			for _, input := range decoded.Inputs {
+				if input.Type == cryptonote.InputKey {
+					spent[input.KeyImage] = true
+				}
+			}
+		}
+	}
+	for _, transaction := range chain.pool {
+		add(transaction)
+	}
+	for _, block := range chain.blocks {
+		for _, transaction := range block.Transactions {
+			add(transaction)
+		}
+	}
+	return spent
+}
+
+/*
+adds a sent transaction to the pool, or returns the status of a stock CryptoNote daemon : "Not relayed" for a transaction
+already in the pool, "Failed" without reason for a blob which can't be decoded, a key image already spent,
+a fee under MinimumFee or a blob over MaxTransactionSize
+*/
+func (chain *chain) send(txHex string) *Error {
+	blob, err := hex.DecodeString(txHex)
+	if err != nil {
+		return &Error{Message: "Failed"}
+	}
+	decoded, err := cryptonote.DecodeTransaction(blob)
+	if err != nil {
+		return &Error{Message: "Failed"}
+	}
+	hash := decoded.Hash()
+	for _, transaction := range chain.pool {
+		if transaction.Hash == hex.EncodeToString(hash[:]) {
+			return &Error{Message: "Not relayed"}
+		}
+	}
+	if len(blob) > MaxTransactionSize {
+		return &Error{Message: "Failed"}
+	}
+
+	spent := chain.spentKeyImages()
+	var amountIn, amountOut uint64
+	mixin := -1
+	for _, input := range decoded.Inputs {
+		if input.Type != cryptonote.InputKey {
+			return &Error{Message: "Failed"}
+		}
+		if spent[input.KeyImage] {
+			return &Error{Message: "Failed"}
+		}
+		amountIn += input.Amount
+		if mixin < 0 || len(input.KeyOffsets)-1 < mixin {
+			mixin = len(input.KeyOffsets) - 1
+		}
+	}
+	for _, output := range decoded.Outputs {
+		amountOut += output.Amount
+	}
+	if amountOut > amountIn || amountIn-amountOut < MinimumFee {
+		return &Error{Message: "Failed"}
+	}
+
+	transaction := Transaction{
+		Hash:        hex.EncodeToString(hash[:]),
+		Fee:         amountIn - amountOut,
+		AmountOut:   amountOut,
+		Size:        uint64(len(blob)),
+		Extra:       hex.EncodeToString(decoded.Extra),
+		ReceiveTime: uint64(time.Now().Unix()),
+		Hex:         txHex,
+	}
+	if mixin > 0 {
+		transaction.Mixin = uint64(mixin)
+	}
+	if extra, _ := cryptonote.ParseExtra(decoded.Extra); extra != nil && extra.PaymentID != nil {
+		transaction.PaymentID = extra.PaymentID.String()
+	}
+	chain.pool = append(chain.pool, transaction)
+	return nil
+}
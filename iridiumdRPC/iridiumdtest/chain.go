@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node in-memory chain
+
+package iridiumdtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// GenesisHash, hash of the Iridium genesis block, returned by getcurrencyid
+	GenesisHash = "9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43"
+	// GenesisTimestamp, timestamp of the fake genesis block, blocks follow every BlockTime seconds
+	GenesisTimestamp = 1546300800
+	// BlockTime, seconds between two fake blocks
+	BlockTime = 120
+	// Reward, atomic units rewarded by each fake block
+	Reward = 1000 * 100000000
+	// Difficulty, difficulty of each fake block
+	Difficulty = 1000000
+)
+
+// Transaction, a transaction of the fake chain, Hex is returned by /gettransactions when not empty
+type Transaction struct {
+	Hash        string
+	Fee         uint64
+	AmountOut   uint64
+	Size        uint64
+	Mixin       uint64
+	PaymentID   string
+	Extra       string
+	ReceiveTime uint64
+	Hex         string
+}
+
+// Block, a block of the fake chain
+type Block struct {
+	MajorVersion uint8
+	MinorVersion uint8
+	Timestamp    uint64
+	PrevHash     string
+	Nonce        uint32
+	Height       uint32
+	Hash         string
+	Difficulty   uint64
+	Reward       uint64
+	Size         uint64
+	Transactions []Transaction
+}
+
+// Hash, returns a fake 32 bytes hash, hex encoded, of the parts
+func Hash(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NewTransaction, returns a transaction with a fake hash derived from name
+func NewTransaction(name string, fee uint64, amountOut uint64) Transaction {
+	return Transaction{Hash: Hash("tx", name), Fee: fee, AmountOut: amountOut, Size: 400, Mixin: 3}
+}
+
+// in-memory chain, not safe for concurrent use, the server serializes the calls
+type chain struct {
+	blocks  []*Block
+	pool    []Transaction
+	branch  int
+	synced  bool
+	network uint32
+}
+
+func newChain() *chain {
+	genesis := &Block{
+		MajorVersion: 1,
+		Timestamp:    GenesisTimestamp,
+		PrevHash:     "0000000000000000000000000000000000000000000000000000000000000000",
+		Hash:         GenesisHash,
+		Difficulty:   1,
+		Reward:       Reward,
+		Size:         80,
+	}
+	return &chain{blocks: []*Block{genesis}, synced: true}
+}
+
+func (chain *chain) top() *Block {
+	return chain.blocks[len(chain.blocks)-1]
+}
+
+// mines a block including the pool transactions
+func (chain *chain) mine() *Block {
+	top := chain.top()
+	height := top.Height + 1
+	block := &Block{
+		MajorVersion: 1,
+		Timestamp:    GenesisTimestamp + uint64(height)*BlockTime,
+		PrevHash:     top.Hash,
+		Nonce:        height,
+		Height:       height,
+		Hash:         Hash("block", strconv.Itoa(chain.branch), strconv.FormatUint(uint64(height), 10)),
+		Difficulty:   Difficulty,
+		Reward:       Reward,
+		Size:         80,
+		Transactions: chain.pool,
+	}
+	for _, transaction := range block.Transactions {
+		block.Size += transaction.Size
+	}
+	chain.pool = nil
+	chain.blocks = append(chain.blocks, block)
+	return block
+}
+
+// removes the top depth blocks, their transactions go back to the pool
+func (chain *chain) rewind(depth int) {
+	if depth >= len(chain.blocks) {
+		depth = len(chain.blocks) - 1
+	}
+	var returned []Transaction
+	for _, block := range chain.blocks[len(chain.blocks)-depth:] {
+		returned = append(returned, block.Transactions...)
+	}
+	chain.blocks = chain.blocks[:len(chain.blocks)-depth]
+	chain.pool = append(returned, chain.pool...)
+	chain.branch++
+}
+
+func (chain *chain) blockByHash(hash string) *Block {
+	for _, block := range chain.blocks {
+		if block.Hash == hash {
+			return block
+		}
+	}
+	return nil
+}
+
+// returns the block including the transaction hash, nil when in the pool, false when unknown
+func (chain *chain) findTransaction(hash string) (*Transaction, *Block, bool) {
+	for i := range chain.pool {
+		if chain.pool[i].Hash == hash {
+			return &chain.pool[i], nil, true
+		}
+	}
+	for _, block := range chain.blocks {
+		for i := range block.Transactions {
+			if block.Transactions[i].Hash == hash {
+				return &block.Transactions[i], block, true
+			}
+		}
+	}
+	return nil, nil, false
+}
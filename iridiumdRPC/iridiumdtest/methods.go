@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node methods answered from the chain
+
+package iridiumdtest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+func wrongParam(message string) *Error {
+	return &Error{Code: codeWrongParam, Message: message}
+}
+
+// block hash param of a call
+func hashParam(params json.RawMessage) (string, *Error) {
+	var request struct {
+		Hash string `json:"hash"`
+	}
+	if json.Unmarshal(params, &request) != nil || len(request.Hash) != 64 {
+		return "", wrongParam("Failed to parse hex representation of hash")
+	}
+	return request.Hash, nil
+}
+
+// block height param of a call, at most the top height
+func (chain *chain) heightParam(params json.RawMessage) (uint32, *Error) {
+	var request struct {
+		Height *uint32 `json:"height"`
+	}
+	if json.Unmarshal(params, &request) != nil || request.Height == nil {
+		return 0, wrongParam("Wrong height param")
+	}
+	if *request.Height > chain.top().Height {
+		return 0, &Error{Code: codeTooBigHeight, Message: "To big height: " + strconv.FormatUint(uint64(*request.Height), 10) +
+			", current blockchain height = " + strconv.Itoa(len(chain.blocks))}
+	}
+	return *request.Height, nil
+}
+
+func blockNotFound(hash string) *Error {
+	return &Error{Code: codeInternalError, Message: "Internal error: can't get block by hash. Hash = " + hash}
+}
+
+// json_rpc methods
+func (server *Server) chainMethods() map[string]Handler {
+	chain := server.chain
+	return map[string]Handler{
+		"getblockcount": func(json.RawMessage) (interface{}, *Error) {
+			return map[string]interface{}{"count": len(chain.blocks), "status": "OK"}, nil
+		},
+		"getcurrencyid": func(json.RawMessage) (interface{}, *Error) {
+			return map[string]interface{}{"currency_id_blob": GenesisHash}, nil
+		},
+		"getlastblockheader": func(json.RawMessage) (interface{}, *Error) {
+			return map[string]interface{}{"block_header": chain.header(chain.top()), "status": "OK"}, nil
+		},
+		"getblockheaderbyhash": func(params json.RawMessage) (interface{}, *Error) {
+			hash, err := hashParam(params)
+			if err != nil {
+				return nil, err
+			}
+			block := chain.blockByHash(hash)
+			if block == nil {
+				return nil, blockNotFound(hash)
+			}
+			return map[string]interface{}{"block_header": chain.header(block), "status": "OK"}, nil
+		},
+		"getblockheaderbyheight": func(params json.RawMessage) (interface{}, *Error) {
+			height, err := chain.heightParam(params)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"block_header": chain.header(chain.blocks[height]), "status": "OK"}, nil
+		},
+		// the daemon lists 31 blocks from height downwards
+		"f_blocks_list_json": func(params json.RawMessage) (interface{}, *Error) {
+			height, err := chain.heightParam(params)
+			if err != nil {
+				return nil, err
+			}
+			blocks := []blockShort{}
+			for h := int64(height); h >= 0 && h >= int64(height)-30; h-- {
+				blocks = append(blocks, chain.short(chain.blocks[h]))
+			}
+			return map[string]interface{}{"blocks": blocks, "status": "OK"}, nil
+		},
+		"f_block_json": func(params json.RawMessage) (interface{}, *Error) {
+			hash, err := hashParam(params)
+			if err != nil {
+				return nil, err
+			}
+			block := chain.blockByHash(hash)
+			if block == nil {
+				return nil, blockNotFound(hash)
+			}
+			return map[string]interface{}{"block": chain.details(block), "status": "OK"}, nil
+		},
+		// a pool transaction has an empty block
+		"f_transaction_json": func(params json.RawMessage) (interface{}, *Error) {
+			hash, err := hashParam(params)
+			if err != nil {
+				return nil, err
+			}
+			transaction, block, found := chain.findTransaction(hash)
+			if !found {
+				return nil, wrongParam("transaction wasn't found. Hash = " + hash)
+			}
+			result := map[string]interface{}{
+				"block":     struct{}{},
+				"tx":        transactionPrefix{Version: 1, Vin: []interface{}{}, Vout: []interface{}{}, Extra: transaction.Extra},
+				"txDetails": summary(transaction),
+				"status":    "OK",
+			}
+			if block != nil {
+				result["block"] = chain.short(block)
+			}
+			return result, nil
+		},
+		"f_on_transactions_pool_json": func(json.RawMessage) (interface{}, *Error) {
+			transactions := []poolTransaction{}
+			for _, transaction := range chain.pool {
+				transactions = append(transactions, poolTransaction{
+					Hash:        transaction.Hash,
+					Fee:         transaction.Fee,
+					AmountOut:   transaction.AmountOut,
+					Size:        transaction.Size,
+					ReceiveTime: transaction.ReceiveTime,
+				})
+			}
+			return map[string]interface{}{"transactions": transactions, "status": "OK"}, nil
+		},
+		"getblocktemplate": func(params json.RawMessage) (interface{}, *Error) {
+			var request struct {
+				ReserveSize   uint64 `json:"reserve_size"`
+				WalletAddress string `json:"wallet_address"`
+			}
+			if json.Unmarshal(params, &request) != nil {
+				return nil, wrongParam("Wrong param")
+			}
+			if request.ReserveSize > maxReserveSize {
+				return nil, &Error{Code: codeTooBigReserveSize, Message: "To big reserved size, maximum 255"}
+			}
+			wallet, err := address.Parse(request.WalletAddress)
+			if err != nil {
+				return nil, &Error{Code: codeWrongWalletAddress, Message: "Failed to parse wallet address"}
+			}
+			block, offset := chain.template(wallet, int(request.ReserveSize))
+			return map[string]interface{}{
+				"blocktemplate_blob": hex.EncodeToString(block.Bytes()),
+				"difficulty":         Difficulty,
+				"height":             chain.top().Height + 1,
+				"reserved_offset":    offset,
+				"status":             "OK",
+			}, nil
+		},
+		// the blob is the only item of an array
+		"submitblock": func(params json.RawMessage) (interface{}, *Error) {
+			var request []string
+			if json.Unmarshal(params, &request) != nil || len(request) != 1 {
+				return nil, wrongParam("Wrong param")
+			}
+			blob, err := hex.DecodeString(request[0])
+			if err != nil {
+				return nil, &Error{Code: codeWrongBlockBlob, Message: "Wrong block blob"}
+			}
+			block, err := cryptonote.DecodeBlock(blob)
+			if err != nil {
+				return nil, &Error{Code: codeWrongBlockBlob, Message: "Wrong block blob"}
+			}
+			if _, accepted := chain.submit(block); !accepted {
+				return nil, &Error{Code: codeBlockNotAccepted, Message: "Block not accepted"}
+			}
+			return map[string]interface{}{"status": "OK"}, nil
+		},
+	}
+}
+
+// plain JSON endpoints
+func (server *Server) chainEndpoints() map[string]Handler {
+	chain := server.chain
+	return map[string]Handler{
+		"getheight": func(json.RawMessage) (interface{}, *Error) {
+			height := uint32(len(chain.blocks))
+			network := height
+			if chain.network > network {
+				network = chain.network
+			}
+			return map[string]interface{}{"height": height, "network_height": network, "status": "OK"}, nil
+		},
+		"getinfo": func(json.RawMessage) (interface{}, *Error) {
+			var transactions int
+			for _, block := range chain.blocks {
+				transactions += len(block.Transactions) + 1
+			}
+			return map[string]interface{}{
+				"alt_blocks_count":           0,
+				"difficulty":                 chain.top().Difficulty,
+				"grey_peerlist_size":         0,
+				"height":                     len(chain.blocks),
+				"incoming_connections_count": 0,
+				"last_known_block_index":     len(chain.blocks) - 1,
+				"outgoing_connections_count": 8,
+				"status":                     "OK",
+				"synced":                     chain.synced,
+				"tx_count":                   transactions,
+				"tx_pool_size":               len(chain.pool),
+				"version":                    "iridiumdtest",
+				"white_peerlist_size":        0,
+			}, nil
+		},
+		"gettransactions": func(params json.RawMessage) (interface{}, *Error) {
+			var request struct {
+				TxsHashes []string `json:"txs_hashes"`
+			}
+			if json.Unmarshal(params, &request) != nil {
+				return nil, &Error{Message: "Failed"}
+			}
+			hexes, missed := []string{}, []string{}
+			for _, hash := range request.TxsHashes {
+				if transaction, _, found := chain.findTransaction(hash); found && transaction.Hex != "" {
+					hexes = append(hexes, transaction.Hex)
+				} else {
+					missed = append(missed, hash)
+				}
+			}
+			return map[string]interface{}{"txs_as_hex": hexes, "missed_tx": missed, "status": "OK"}, nil
+		},
+		"sendrawtransaction": func(params json.RawMessage) (interface{}, *Error) {
+			var request struct {
+				TxAsHex string `json:"tx_as_hex"`
+			}
+			if json.Unmarshal(params, &request) != nil {
+				return nil, &Error{Message: "Failed"}
+			}
+			if err := chain.send(request.TxAsHex); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"status": "OK"}, nil
+		},
+		"get_generated_coins": func(json.RawMessage) (interface{}, *Error) {
+			return map[string]interface{}{"alreadyGeneratedCoins": uint64(len(chain.blocks)) * Reward, "status": "OK"}, nil
+		},
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node block templates and submitted blocks
+
+package iridiumdtest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cnhash"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+const (
+	// largest reserve accepted by getblocktemplate
+	maxReserveSize = 255
+	// unlock window of the miner transaction outputs
+	minedMoneyUnlockWindow = 10
+)
+
+func fakeHash(parts ...string) cnhash.Hash {
+	var hash cnhash.Hash
+	decoded, _ := hex.DecodeString(Hash(parts...))
+	copy(hash[:], decoded)
+	return hash
+}
+
+/*
+block template on top of the chain, including the pool transactions with a hex hash,
+the miner transaction pays the reward to the spend key of wallet, there is no key derivation,
+its extra has a fake public key and a nonce of reserveSize zero bytes starting at the returned offset,
+the offset is 0 without reserve
+*/
+func (chain *chain) template(wallet *address.Address, reserveSize int) (*cryptonote.Block, int) {
+	top := chain.top()
+	height := top.Height + 1
+	block := &cryptonote.Block{
+		BlockHeader: cryptonote.BlockHeader{
+			MajorVersion: 1,
+			Timestamp:    GenesisTimestamp + uint64(height)*BlockTime,
+		},
+	}
+	prevHash, _ := hex.DecodeString(top.Hash)
+	copy(block.PrevHash[:], prevHash)
+
+	txKey := fakeHash("txkey", strconv.Itoa(chain.branch), strconv.FormatUint(uint64(height), 10))
+	extra := append([]byte{cryptonote.ExtraTagPublicKey}, txKey[:]...)
+	if reserveSize > 0 {
+		extra = append(extra, cryptonote.ExtraTagNonce, byte(reserveSize))
+		extra = append(extra, make([]byte, reserveSize)...)
+	}
+	block.MinerTx.TransactionPrefix = cryptonote.TransactionPrefix{
+		Version:    1,
+		UnlockTime: uint64(height) + minedMoneyUnlockWindow,
+		Inputs:     []cryptonote.Input{{Type: cryptonote.InputGen, Height: height}},
+		Outputs:    []cryptonote.Output{{Amount: Reward, Key: wallet.SpendKey}},
+		Extra:      extra,
+	}
+
+	for _, transaction := range chain.pool {
+		var hash cnhash.Hash
+		if decoded, err := hex.DecodeString(transaction.Hash); err == nil && len(decoded) == len(hash) {
+			copy(hash[:], decoded)
+			block.TxHashes = append(block.TxHashes, hash)
+		}
+	}
+
+	// like the daemon, the reserve follows the public key, the nonce tag and its size
+	if reserveSize == 0 {
+		return block, 0
+	}
+	return block, bytes.Index(block.Bytes(), txKey[:]) + len(txKey) + 2
+}
+
+/*
+adds a submitted block on top of the chain, false when it doesn't follow the top block,
+the proof of work is not checked, its transactions found in the pool leave it
+*/
+func (chain *chain) submit(submitted *cryptonote.Block) (*Block, bool) {
+	top := chain.top()
+	if hex.EncodeToString(submitted.PrevHash[:]) != top.Hash {
+		return nil, false
+	}
+	hash := submitted.Hash()
+	block := &Block{
+		MajorVersion: submitted.MajorVersion,
+		MinorVersion: submitted.MinorVersion,
+		Timestamp:    submitted.Timestamp,
+		PrevHash:     top.Hash,
+		Nonce:        submitted.Nonce,
+		Height:       top.Height + 1,
+		Hash:         hex.EncodeToString(hash[:]),
+		Difficulty:   Difficulty,
+		Reward:       Reward,
+		Size:         uint64(len(submitted.MinerTx.Bytes())),
+	}
+
+	included := make(map[string]bool)
+	for _, hash := range submitted.TxHashes {
+		included[hex.EncodeToString(hash[:])] = true
+	}
+	var pool []Transaction
+	for _, transaction := range chain.pool {
+		if included[transaction.Hash] {
+			block.Transactions = append(block.Transactions, transaction)
+			block.Size += transaction.Size
+		} else {
+			pool = append(pool, transaction)
+		}
+	}
+	chain.pool = pool
+	chain.blocks = append(chain.blocks, block)
+	return block, true
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node responses, shaped like the daemon ones
+
+package iridiumdtest
+
+type blockHeader struct {
+	MajorVersion uint8  `json:"major_version"`
+	MinorVersion uint8  `json:"minor_version"`
+	Timestamp    uint64 `json:"timestamp"`
+	PrevHash     string `json:"prev_hash"`
+	Nonce        uint32 `json:"nonce"`
+	OrphanStatus bool   `json:"orphan_status"`
+	Height       uint32 `json:"height"`
+	Depth        uint32 `json:"depth"`
+	Hash         string `json:"hash"`
+	Difficulty   uint64 `json:"difficulty"`
+	Reward       uint64 `json:"reward"`
+}
+
+type blockShort struct {
+	CumulSize  uint64 `json:"cumul_size"`
+	Difficulty uint64 `json:"difficulty"`
+	Hash       string `json:"hash"`
+	Height     uint32 `json:"height"`
+	Reward     uint64 `json:"reward"`
+	Timestamp  uint64 `json:"timestamp"`
+	TxCount    uint32 `json:"tx_count"`
+}
+
+type transactionShort struct {
+	Hash      string `json:"hash"`
+	Fee       uint64 `json:"fee"`
+	AmountOut uint64 `json:"amount_out"`
+	Size      uint64 `json:"size"`
+}
+
+type blockDetails struct {
+	blockHeader
+	BlockSize                    uint64             `json:"blockSize"`
+	SizeMedian                   uint64             `json:"sizeMedian"`
+	EffectiveSizeMedian          uint64             `json:"effectiveSizeMedian"`
+	TransactionsCumulativeSize   uint64             `json:"transactionsCumulativeSize"`
+	AlreadyGeneratedCoins        uint64             `json:"alreadyGeneratedCoins"`
+	AlreadyGeneratedTransactions uint64             `json:"alreadyGeneratedTransactions"`
+	BaseReward                   uint64             `json:"baseReward"`
+	Penalty                      float64            `json:"penalty"`
+	TotalFeeAmount               uint64             `json:"totalFeeAmount"`
+	Transactions                 []transactionShort `json:"transactions"`
+}
+
+type transactionPrefix struct {
+	Version    uint8         `json:"version"`
+	UnlockTime uint64        `json:"unlock_time"`
+	Vin        []interface{} `json:"vin"`
+	Vout       []interface{} `json:"vout"`
+	Extra      string        `json:"extra"`
+}
+
+type transactionSummary struct {
+	Hash      string `json:"hash"`
+	Fee       uint64 `json:"fee"`
+	AmountOut uint64 `json:"amount_out"`
+	Size      uint64 `json:"size"`
+	Mixin     uint64 `json:"mixin"`
+	PaymentID string `json:"paymentId"`
+}
+
+type poolTransaction struct {
+	Hash        string `json:"hash"`
+	Fee         uint64 `json:"fee"`
+	AmountOut   uint64 `json:"amount_out"`
+	Size        uint64 `json:"size"`
+	ReceiveTime uint64 `json:"receive_time"`
+}
+
+func (chain *chain) header(block *Block) blockHeader {
+	return blockHeader{
+		MajorVersion: block.MajorVersion,
+		MinorVersion: block.MinorVersion,
+		Timestamp:    block.Timestamp,
+		PrevHash:     block.PrevHash,
+		Nonce:        block.Nonce,
+		Height:       block.Height,
+		Depth:        chain.top().Height - block.Height,
+		Hash:         block.Hash,
+		Difficulty:   block.Difficulty,
+		Reward:       block.Reward,
+	}
+}
+
+func (chain *chain) short(block *Block) blockShort {
+	return blockShort{
+		CumulSize:  block.Size,
+		Difficulty: block.Difficulty,
+		Hash:       block.Hash,
+		Height:     block.Height,
+		Reward:     block.Reward,
+		Timestamp:  block.Timestamp,
+		TxCount:    uint32(len(block.Transactions)) + 1,
+	}
+}
+
+func (chain *chain) details(block *Block) blockDetails {
+	details := blockDetails{
+		blockHeader:                  chain.header(block),
+		BlockSize:                    block.Size,
+		SizeMedian:                   block.Size,
+		EffectiveSizeMedian:          block.Size,
+		AlreadyGeneratedCoins:        uint64(block.Height+1) * Reward,
+		AlreadyGeneratedTransactions: uint64(block.Height + 1),
+		BaseReward:                   block.Reward,
+		Transactions:                 []transactionShort{},
+	}
+	for _, transaction := range block.Transactions {
+		details.TransactionsCumulativeSize += transaction.Size
+		details.TotalFeeAmount += transaction.Fee
+		details.AlreadyGeneratedTransactions++
+		details.Transactions = append(details.Transactions, transactionShort{
+			Hash: transaction.Hash, Fee: transaction.Fee, AmountOut: transaction.AmountOut, Size: transaction.Size,
+		})
+	}
+	return details
+}
+
+func summary(transaction *Transaction) transactionSummary {
+	return transactionSummary{
+		Hash:      transaction.Hash,
+		Fee:       transaction.Fee,
+		AmountOut: transaction.AmountOut,
+		Size:      transaction.Size,
+		Mixin:     transaction.Mixin,
+		PaymentID: transaction.PaymentID,
+	}
+}
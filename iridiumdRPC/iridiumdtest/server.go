@@ -0,0 +1,385 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium fake node for tests, serving a scriptable in-memory chain over http
+
+package iridiumdtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// daemon error codes
+const (
+	codeMethodNotFound     = -32601
+	codeWrongParam         = -1
+	codeTooBigHeight       = -2
+	codeTooBigReserveSize  = -3
+	codeWrongWalletAddress = -4
+	codeInternalError      = -5
+	codeWrongBlockBlob     = -6
+	codeBlockNotAccepted   = -7
+	codeCoreBusy           = -9
+)
+
+// Error, json_rpc error object returned by a Handler
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err *Error) Error() string {
+	return err.Message + " (" + strconv.Itoa(err.Code) + ")"
+}
+
+/*
+Handler, answers a json_rpc method or a plain JSON endpoint with its params,
+the result is sent as is, a non nil error is sent as the json_rpc error object (or the status of an endpoint)
+*/
+type Handler func(params json.RawMessage) (result interface{}, err *Error)
+
+/*
+Fault, misbehavior injected in the answers to Method, or to every request when Method is empty,
+for Count requests, or until ClearFaults when Count is 0
+*/
+type Fault struct {
+	// Method, json_rpc method or endpoint name ("getinfo", "getblockcount"...)
+	Method string
+	// Latency, delay before answering
+	Latency time.Duration
+	// Busy, answers the core busy error, or the "BUSY" status of an endpoint
+	Busy bool
+	// Malformed, answers a truncated JSON body, the whole array reply of a batch holding Method
+	Malformed bool
+	// WrongID, answers with another json_rpc id than the request one
+	WrongID bool
+	// HTTPStatus, answers this http status instead of 200
+	HTTPStatus int
+	// Count, requests affected, every request until ClearFaults when 0
+	Count int
+
+	used int
+}
+
+/*
+Server, fake iridiumd serving getheight, getinfo, gettransactions, get_generated_coins and json_rpc,
+batch requests included, from an in-memory chain starting at the Iridium genesis block,
+the chain, the pool and the faults are scripted with the Server methods, which are safe for concurrent use
+*/
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	chain    *chain
+	faults   []*Fault
+	handlers map[string]Handler
+	requests []string
+	done     chan struct{}
+}
+
+// NewServer, starts a fake node, Close stops it
+func NewServer() *Server {
+	server := &Server{chain: newChain(), handlers: make(map[string]Handler), done: make(chan struct{})}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// Close, stops the server, requests waiting for an injected latency are answered at once
+func (server *Server) Close() {
+	server.mu.Lock()
+	select {
+	case <-server.done:
+	default:
+		close(server.done)
+	}
+	server.mu.Unlock()
+	server.Server.Close()
+}
+
+// Address, host of the server, for the Iridiumd Address field
+func (server *Server) Address() string {
+	return server.Listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port, port of the server, for the Iridiumd Port field
+func (server *Server) Port() int {
+	return server.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// Height, height of the top block
+func (server *Server) Height() uint32 {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return server.chain.top().Height
+}
+
+// Block, returns the block at height
+func (server *Server) Block(height uint32) (Block, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if height >= uint32(len(server.chain.blocks)) {
+		return Block{}, false
+	}
+	return *server.chain.blocks[height], true
+}
+
+// Mine, adds count blocks on top of the chain, the first one includes the pool transactions
+func (server *Server) Mine(count int) []Block {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	blocks := make([]Block, count)
+	for i := range blocks {
+		blocks[i] = *server.chain.mine()
+	}
+	return blocks
+}
+
+/*
+Reorg, replaces the top depth blocks by count blocks of a new branch,
+the transactions of the removed blocks go back to the pool and are included in the first new block
+*/
+func (server *Server) Reorg(depth int, count int) []Block {
+	server.mu.Lock()
+	server.chain.rewind(depth)
+	server.mu.Unlock()
+	return server.Mine(count)
+}
+
+// AddToPool, adds transactions to the pool
+func (server *Server) AddToPool(transactions ...Transaction) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.chain.pool = append(server.chain.pool, transactions...)
+}
+
+// Evict, removes transactions from the pool without mining them
+func (server *Server) Evict(hashes ...string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	evicted := make(map[string]bool)
+	for _, hash := range hashes {
+		evicted[hash] = true
+	}
+	var pool []Transaction
+	for _, transaction := range server.chain.pool {
+		if !evicted[transaction.Hash] {
+			pool = append(pool, transaction)
+		}
+	}
+	server.chain.pool = pool
+}
+
+// SetSynced, synced flag of getinfo, true by default
+func (server *Server) SetSynced(synced bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.chain.synced = synced
+}
+
+// SetNetworkHeight, network height of getheight, the chain height when lower
+func (server *Server) SetNetworkHeight(height uint32) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.chain.network = height
+}
+
+// Inject, adds a fault, the first matching fault applies
+func (server *Server) Inject(fault Fault) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.faults = append(server.faults, &fault)
+}
+
+// ClearFaults, removes all the faults
+func (server *Server) ClearFaults() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.faults = nil
+}
+
+/*
+Handle, answers method with handler instead of the chain, a nil handler restores the default answer,
+handlers run while the server is locked and must not call the Server methods
+*/
+func (server *Server) Handle(method string, handler Handler) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if handler == nil {
+		delete(server.handlers, method)
+		return
+	}
+	server.handlers[method] = handler
+}
+
+// Requests, methods and endpoints received so far, in order
+func (server *Server) Requests() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]string(nil), server.requests...)
+}
+
+// returns the fault applying to a request of method, and consumes it
+func (server *Server) fault(method string) *Fault {
+	for i, fault := range server.faults {
+		if fault.Method != "" && fault.Method != method {
+			continue
+		}
+		fault.used++
+		if fault.Count > 0 && fault.used >= fault.Count {
+			server.faults = append(server.faults[:i:i], server.faults[i+1:]...)
+		}
+		return fault
+	}
+	return nil
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	server.mu.Lock()
+	var answer interface{}
+	var faults []*Fault
+	switch {
+	case path != "json_rpc":
+		server.requests = append(server.requests, path)
+		fault := server.fault(path)
+		faults = append(faults, fault)
+		answer = server.plain(path, body, fault)
+	case bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")):
+		var requests []rpcRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			answer = parseError()
+			break
+		}
+		responses := make([]rpcResponse, len(requests))
+		for i, request := range requests {
+			server.requests = append(server.requests, request.Method)
+			fault := server.fault(request.Method)
+			faults = append(faults, fault)
+			responses[i] = server.call(request, fault)
+		}
+		answer = responses
+	default:
+		var request rpcRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			answer = parseError()
+			break
+		}
+		server.requests = append(server.requests, request.Method)
+		fault := server.fault(request.Method)
+		faults = append(faults, fault)
+		answer = server.call(request, fault)
+	}
+	server.mu.Unlock()
+
+	// http level faults apply to the whole request
+	var latency time.Duration
+	status, malformed := http.StatusOK, false
+	for _, fault := range faults {
+		if fault == nil {
+			continue
+		}
+		if fault.Latency > latency {
+			latency = fault.Latency
+		}
+		if fault.HTTPStatus != 0 {
+			status = fault.HTTPStatus
+		}
+		malformed = malformed || fault.Malformed
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		case <-server.done:
+		}
+	}
+	if status == http.StatusNotFound || answer == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	response, _ := json.Marshal(answer)
+	if malformed {
+		response = response[:len(response)/2]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response)
+}
+
+func parseError() rpcResponse {
+	return rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: -32700, Message: "Parse error"}}
+}
+
+// answers a json_rpc call
+func (server *Server) call(request rpcRequest, fault *Fault) rpcResponse {
+	response := rpcResponse{JSONRPC: "2.0", ID: request.ID}
+	if len(response.ID) == 0 {
+		response.ID = json.RawMessage("null")
+	}
+	if fault != nil && fault.WrongID {
+		response.ID = json.RawMessage(`"wrong-id"`)
+	}
+	if fault != nil && fault.Busy {
+		response.Error = &Error{Code: codeCoreBusy, Message: "Core is busy"}
+		return response
+	}
+	handler, found := server.handlers[request.Method]
+	if !found {
+		handler, found = server.chainMethods()[request.Method]
+	}
+	if !found {
+		response.Error = &Error{Code: codeMethodNotFound, Message: "Method not found"}
+		return response
+	}
+	response.Result, response.Error = handler(request.Params)
+	return response
+}
+
+// answers a plain JSON endpoint, nil for an unknown one
+func (server *Server) plain(path string, body []byte, fault *Fault) interface{} {
+	if fault != nil && fault.Busy {
+		return map[string]string{"status": "BUSY"}
+	}
+	handler, found := server.handlers[path]
+	if !found {
+		handler, found = server.chainEndpoints()[path]
+	}
+	if !found {
+		return nil
+	}
+	result, err := handler(body)
+	if err != nil {
+		return map[string]string{"status": err.Message}
+	}
+	return result
+}
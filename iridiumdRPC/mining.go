@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node mining methods, block templates and solved blocks
+
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// MaxReserveSize, largest reserve asked to getblocktemplate, the daemon answers ErrTooBigReserveSize above
+const MaxReserveSize = 255
+
+/*
+getblocktemplate, returns a block to mine paying walletAddress, with reserveSize bytes free at ReservedOffset for an extra nonce
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : reserveSize uint32, walletAddress string
+*/
+func (node *Iridiumd) BlockTemplate(reserveSize uint32, walletAddress string, id ...string) (*BlockTemplate, error) {
+	return node.BlockTemplateContext(context.Background(), reserveSize, walletAddress, id...)
+}
+
+// BlockTemplateContext, same as BlockTemplate, the context cancels the request
+func (node *Iridiumd) BlockTemplateContext(ctx context.Context, reserveSize uint32, walletAddress string, id ...string) (*BlockTemplate, error) {
+	if reserveSize > MaxReserveSize {
+		return nil, fmt.Errorf("%w : %d, maximum %d", ErrTooBigReserveSize, reserveSize, MaxReserveSize)
+	}
+	payload := idParams(id)
+	payload["reserve_size"] = reserveSize
+	payload["wallet_address"] = walletAddress
+	var result BlockTemplate
+	if err := node.postResult(ctx, "getblocktemplate", payload, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+/*
+submitblock, submits a solved block blob, hex encoded, the blob is checked locally before being sent
+a rejected block returns an error matching ErrBlockNotAccepted, an invalid blob ErrWrongBlockBlob
+submitblock is never retried
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : blockBlob string
+*/
+func (node *Iridiumd) SubmitBlock(blockBlob string, id ...string) error {
+	return node.SubmitBlockContext(context.Background(), blockBlob, id...)
+}
+
+// SubmitBlockContext, same as SubmitBlock, the context cancels the request
+func (node *Iridiumd) SubmitBlockContext(ctx context.Context, blockBlob string, id ...string) error {
+	if _, err := hex.DecodeString(blockBlob); err != nil || blockBlob == "" {
+		return fmt.Errorf("%w : not a hex encoded blob", ErrWrongBlockBlob)
+	}
+	var requestID string
+	if len(id) != 0 {
+		requestID = id[0]
+	}
+	// the daemon takes the blob as the only item of an array
+	body, err := node.postRequest(ctx, "submitblock", []string{blockBlob}, requestID)
+	if err != nil {
+		return err
+	}
+	var envelope struct {
+		Result statusResult `json:"result"`
+	}
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	if envelope.Result.Status != "OK" {
+		return fmt.Errorf("%w : status %q", ErrBlockNotAccepted, envelope.Result.Status)
+	}
+	return nil
+}
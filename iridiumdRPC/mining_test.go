@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node mining methods tests
+package iridiumdRPC
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
+)
+
+// a fake node of its own, the submitted blocks change its chain
+func newMiningNode(t *testing.T) (*iridiumdtest.Server, *Iridiumd) {
+	daemon := iridiumdtest.NewServer()
+	daemon.Mine(5)
+	miningNode, err := NewIridiumd(daemon.URL)
+	if err != nil {
+		daemon.Close()
+		t.Fatalf("%s %s", er, err)
+	}
+	return daemon, miningNode
+}
+
+func miningWallet() string {
+	wallet := address.Address{Network: address.Mainnet}
+	for i := range wallet.SpendKey {
+		wallet.SpendKey[i], wallet.ViewKey[i] = byte(i), byte(2*i)
+	}
+	return wallet.String()
+}
+
+func TestIridiumd_BlockTemplate(t *testing.T) {
+	daemon, miningNode := newMiningNode(t)
+	defer daemon.Close()
+	pending := iridiumdtest.NewTransaction("pending", 100000, 500000000)
+	daemon.AddToPool(pending)
+
+	template, err := miningNode.BlockTemplate(8, miningWallet())
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if template.Height != 6 || template.Difficulty != iridiumdtest.Difficulty || template.Status != "OK" {
+		t.Errorf("%stemplate %+v", er, template)
+	}
+	blob, err := hex.DecodeString(template.BlocktemplateBlob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	block, err := cryptonote.DecodeBlock(blob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	top, _ := daemon.Block(5)
+	if hex.EncodeToString(block.PrevHash[:]) != top.Hash {
+		t.Errorf("%stemplate on top of %x, want %s", er, block.PrevHash, top.Hash)
+	}
+	if len(block.TxHashes) != 1 || hex.EncodeToString(block.TxHashes[0][:]) != pending.Hash {
+		t.Errorf("%stemplate transactions %x, want %s", er, block.TxHashes, pending.Hash)
+	}
+	extra, err := cryptonote.ParseExtra(block.MinerTx.Extra)
+	if err != nil || len(extra.Nonce) != 8 {
+		t.Fatalf("%sminer transaction extra %+v, %v", er, extra, err)
+	}
+
+	// the reserve is the extra nonce
+	for i := 0; i < 8; i++ {
+		blob[int(template.ReservedOffset)+i] = byte(i + 1)
+	}
+	block, err = cryptonote.DecodeBlock(blob)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	extra, _ = cryptonote.ParseExtra(block.MinerTx.Extra)
+	if hex.EncodeToString(extra.Nonce) != "0102030405060708" {
+		t.Errorf("%sreserve at %d filled the nonce %x", er, template.ReservedOffset, extra.Nonce)
+	}
+	t.Logf("%stemplate at height %d, reserve at %d", ok, template.Height, template.ReservedOffset)
+
+	if template, err = miningNode.BlockTemplate(0, miningWallet()); err != nil || template.ReservedOffset != 0 {
+		t.Errorf("%swithout reserve : %+v, %v", er, template, err)
+	}
+}
+
+func TestIridiumd_BlockTemplateErrors(t *testing.T) {
+	daemon, miningNode := newMiningNode(t)
+	defer daemon.Close()
+
+	if _, err := miningNode.BlockTemplate(MaxReserveSize+1, miningWallet()); !errors.Is(err, ErrTooBigReserveSize) {
+		t.Errorf("%swant %v, got %v", er, ErrTooBigReserveSize, err)
+	}
+	if len(daemon.Requests()) != 0 {
+		t.Errorf("%sa too big reserve was sent : %v", er, daemon.Requests())
+	}
+	var rpcError *RPCError
+	_, err := miningNode.BlockTemplate(8, "ir-not-an-address")
+	if !errors.Is(err, ErrWrongWalletAddress) || !errors.As(err, &rpcError) || rpcError.Code != ErrorCodeWrongWalletAddress {
+		t.Errorf("%swant %v, got %v", er, ErrWrongWalletAddress, err)
+	}
+}
+
+func TestIridiumd_SubmitBlock(t *testing.T) {
+	daemon, miningNode := newMiningNode(t)
+	defer daemon.Close()
+	daemon.AddToPool(iridiumdtest.NewTransaction("pending", 100000, 500000000))
+
+	template, err := miningNode.BlockTemplate(8, miningWallet())
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	blob, _ := hex.DecodeString(template.BlocktemplateBlob)
+	block, _ := cryptonote.DecodeBlock(blob)
+	block.Nonce = 42
+	solved := hex.EncodeToString(block.Bytes())
+
+	if err := miningNode.SubmitBlock(solved); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	mined, found := daemon.Block(6)
+	hash := block.Hash()
+	if !found || mined.Hash != hex.EncodeToString(hash[:]) || mined.Nonce != 42 || len(mined.Transactions) != 1 {
+		t.Errorf("%ssubmitted block recorded as %+v", er, mined)
+	}
+	t.Logf("%sblock %s accepted at height %d", ok, mined.Hash, mined.Height)
+
+	// the same block is not on top of the chain anymore
+	var rpcError *RPCError
+	err = miningNode.SubmitBlock(solved)
+	if !errors.Is(err, ErrBlockNotAccepted) || !errors.As(err, &rpcError) || rpcError.Code != ErrorCodeBlockNotAccepted {
+		t.Errorf("%swant %v, got %v", er, ErrBlockNotAccepted, err)
+	}
+	if err := miningNode.SubmitBlock("00"); !errors.Is(err, ErrWrongBlockBlob) {
+		t.Errorf("%swant %v, got %v", er, ErrWrongBlockBlob, err)
+	}
+
+	// checked before being sent
+	sent := len(daemon.Requests())
+	for _, invalid := range []string{"", "not hex", "0"} {
+		if err := miningNode.SubmitBlock(invalid); !errors.Is(err, ErrWrongBlockBlob) {
+			t.Errorf("%s%q : want %v, got %v", er, invalid, ErrWrongBlockBlob, err)
+		}
+	}
+	if len(daemon.Requests()) != sent {
+		t.Errorf("%sinvalid blobs were sent : %v", er, daemon.Requests()[sent:])
+	}
+}
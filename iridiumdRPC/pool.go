@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium nodes pool, health checks and failover
+
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SelectionStrategy, order in which the pool nodes are tried
+type SelectionStrategy int
+
+const (
+	// RoundRobin, healthy nodes in turn
+	RoundRobin SelectionStrategy = iota
+	// LowestLatency, the healthy node with the fastest health check first
+	LowestLatency
+	// HighestHeight, the healthy node with the highest height first
+	HighestHeight
+)
+
+// PoolConfig, selection and health checks settings of a pool
+type PoolConfig struct {
+	// Strategy, node selection, RoundRobin by default
+	Strategy SelectionStrategy
+	// MaxHeightLag, blocks a node can lag behind the network before leaving the rotation
+	MaxHeightLag uint32
+	// HealthCheckInterval, delay between two health checks of Start, 30 seconds when 0
+	HealthCheckInterval time.Duration
+}
+
+// NodeStatus, last known state of a pool node
+type NodeStatus struct {
+	Node          *Iridiumd
+	Healthy       bool
+	Synced        bool
+	Height        uint32
+	NetworkHeight uint32
+	Latency       time.Duration
+	CheckedAt     time.Time
+	Err           error
+}
+
+/*
+NodePool, spreads the calls across several nodes, it has the same methods as Iridiumd
+lagging, unsynced or unreachable nodes are taken out of the rotation by health checks,
+a call failing on a node for a network or http error, or answered busy, is sent to the next one
+*/
+type NodePool struct {
+	*Iridiumd
+
+	config  PoolConfig
+	mu      sync.Mutex
+	members []*NodeStatus
+	next    uint32
+}
+
+// ErrNoNode, the pool has no node
+var ErrNoNode = errors.New("no node in pool")
+
+/*
+NewNodePool, returns a pool of the given nodes, all nodes are in the rotation until the first health check,
+options configure the pool calls (ids, request hook, retry), transport options are ignored as the nodes use their own
+*/
+func NewNodePool(nodes []*Iridiumd, config PoolConfig, options ...Option) (*NodePool, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoNode
+	}
+	clientConfig := &clientConfig{}
+	for _, option := range options {
+		if err := option(clientConfig); err != nil {
+			return nil, err
+		}
+	}
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = 30 * time.Second
+	}
+
+	pool := &NodePool{config: config}
+	for _, node := range nodes {
+		pool.members = append(pool.members, &NodeStatus{Node: node, Healthy: true})
+	}
+	pool.Iridiumd = &Iridiumd{config: clientConfig, pool: pool}
+	return pool, nil
+}
+
+// Status, returns a snapshot of the nodes state
+func (pool *NodePool) Status() []NodeStatus {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	status := make([]NodeStatus, len(pool.members))
+	for i, member := range pool.members {
+		status[i] = *member
+	}
+	return status
+}
+
+/*
+CheckHealth, checks all nodes with getheight and getinfo, a node stays in the rotation
+when it answers, is synced and lags at most MaxHeightLag blocks behind the network height,
+the network height is the highest height or network_height reported by the nodes
+*/
+func (pool *NodePool) CheckHealth(ctx context.Context) {
+	pool.mu.Lock()
+	checks := make([]NodeStatus, len(pool.members))
+	for i, member := range pool.members {
+		checks[i].Node = member.Node
+	}
+	pool.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(check *NodeStatus) {
+			defer wg.Done()
+			node := check.Node
+			start := time.Now()
+			height, err := node.HeightContext(ctx)
+			if err != nil {
+				check.Err = err
+				return
+			}
+			check.Latency = time.Since(start)
+			info, err := node.InfoContext(ctx)
+			if err != nil {
+				check.Err = err
+				return
+			}
+			check.Height, check.NetworkHeight, check.Synced = height.Height, height.NetworkHeight, info.Synced
+		}(&checks[i])
+	}
+	wg.Wait()
+
+	var networkHeight uint32
+	for _, check := range checks {
+		if check.Err == nil {
+			networkHeight = maxHeight(networkHeight, maxHeight(check.Height, check.NetworkHeight))
+		}
+	}
+	now := time.Now()
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for i := range checks {
+		check := &checks[i]
+		check.CheckedAt = now
+		check.Healthy = check.Err == nil && check.Synced && check.Height+pool.config.MaxHeightLag >= networkHeight
+		pool.members[i] = check
+	}
+}
+
+// Start, checks the nodes health now and every HealthCheckInterval until ctx is done
+func (pool *NodePool) Start(ctx context.Context) {
+	pool.CheckHealth(ctx)
+	go func() {
+		ticker := time.NewTicker(pool.config.HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pool.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+func maxHeight(a, b uint32) uint32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// returns the nodes to try in order : healthy ones by strategy, then the others as a last resort
+func (pool *NodePool) candidates() []*Iridiumd {
+	pool.mu.Lock()
+	var healthy, unhealthy []*NodeStatus
+	for _, member := range pool.members {
+		if member.Healthy {
+			healthy = append(healthy, member)
+		} else {
+			unhealthy = append(unhealthy, member)
+		}
+	}
+	pool.mu.Unlock()
+
+	switch pool.config.Strategy {
+	case LowestLatency:
+		sort.SliceStable(healthy, func(i, j int) bool { return healthy[i].Latency < healthy[j].Latency })
+	case HighestHeight:
+		sort.SliceStable(healthy, func(i, j int) bool { return healthy[i].Height > healthy[j].Height })
+	default:
+		if len(healthy) > 0 {
+			shift := int(atomic.AddUint32(&pool.next, 1)-1) % len(healthy)
+			rotated := make([]*NodeStatus, 0, len(healthy))
+			healthy = append(append(rotated, healthy[shift:]...), healthy[:shift]...)
+		}
+	}
+
+	nodes := make([]*Iridiumd, 0, len(pool.members))
+	for _, member := range append(healthy, unhealthy...) {
+		nodes = append(nodes, member.Node)
+	}
+	return nodes
+}
+
+// takes a failing node out of the rotation until the next health check
+func (pool *NodePool) markFailed(node *Iridiumd, err error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for _, member := range pool.members {
+		if member.Node == node {
+			member.Healthy = false
+			member.Err = err
+		}
+	}
+}
+
+/*
+sends a request to the first node answering, nodes failing with a network or http error or answering busy are skipped,
+a request which is not idempotent goes to the next node only when the failing one can't have received it, or refused it busy
+*/
+func (pool *NodePool) send(ctx context.Context, httpMethod string, path string, jsonPayload []byte, idempotent bool) ([]byte, error) {
+	var lastErr error
+	for _, node := range pool.candidates() {
+		body, err := node.send(ctx, httpMethod, path, jsonPayload, idempotent)
+		if err == nil {
+			err = busyAnswer(body)
+		}
+		if err == nil {
+			return body, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !IsRetryable(err) {
+			return nil, err
+		}
+		pool.markFailed(node, err)
+		if !idempotent && !notSent(err) && !errors.Is(err, ErrNodeBusy) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// the connection failed, the node never received the request
+func notSent(err error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) && opError.Op == "dial"
+}
+
+// the core busy error or the "BUSY" status answered by a node, which didn't run the request, nil otherwise
+func busyAnswer(body []byte) error {
+	var envelope rpcResponse
+	if json.Unmarshal(body, &envelope) != nil {
+		// batch arrays, their calls are checked one by one
+		return nil
+	}
+	if envelope.Error != nil && errors.Is(envelope.Error, ErrNodeBusy) {
+		return envelope.Error
+	}
+	if len(envelope.Result) != 0 {
+		return checkStatus(envelope.Result)
+	}
+	return checkStatus(body)
+}
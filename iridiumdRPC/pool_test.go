@@ -0,0 +1,206 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium nodes pool tests
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
+)
+
+// fake node answering getheight, getinfo and getblockcount with its height, counting its calls
+type poolTestNode struct {
+	height uint32
+	synced bool
+	calls  int32
+	server *httptest.Server
+	node   *Iridiumd
+}
+
+func newPoolTestNode(t *testing.T, height uint32, synced bool) *poolTestNode {
+	fake := &poolTestNode{height: height, synced: synced}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fake.calls, 1)
+		height := strconv.Itoa(int(fake.height))
+		switch r.URL.Path {
+		case "/getheight":
+			w.Write([]byte(`{"height": ` + height + `, "network_height": ` + height + `, "status": "OK"}`))
+		case "/getinfo":
+			w.Write([]byte(`{"height": ` + height + `, "synced": ` + strconv.FormatBool(fake.synced) + `, "status": "OK"}`))
+		default:
+			echoHandler(`{"count": `+height+`, "status": "OK"}`, "")(w, r)
+		}
+	}))
+	var err error
+	if fake.node, err = NewIridiumd(fake.server.URL); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	return fake
+}
+
+func TestNodePool_HealthCheck(t *testing.T) {
+	good := newPoolTestNode(t, 1000, true)
+	defer good.server.Close()
+	lagging := newPoolTestNode(t, 900, true)
+	defer lagging.server.Close()
+	syncing := newPoolTestNode(t, 1000, false)
+	defer syncing.server.Close()
+	down := newPoolTestNode(t, 1000, true)
+	down.server.Close()
+
+	pool, err := NewNodePool([]*Iridiumd{good.node, lagging.node, syncing.node, down.node}, PoolConfig{MaxHeightLag: 2})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	pool.CheckHealth(context.Background())
+	for i, status := range pool.Status() {
+		if status.Healthy != (i == 0) {
+			t.Errorf("%snode %d healthy : %t (%+v)", er, i, status.Healthy, status)
+		}
+	}
+
+	// all calls go to the only healthy node
+	for i := 0; i < 5; i++ {
+		if count, err := pool.BlockCount(); err != nil || count != 1000 {
+			t.Errorf("%sGetBlockCount returns %d, %v", er, count, err)
+		}
+	}
+	t.Logf("%spool status %+v", ok, pool.Status()[0])
+}
+
+func TestNodePool_Failover(t *testing.T) {
+	first := newPoolTestNode(t, 1000, true)
+	second := newPoolTestNode(t, 1000, true)
+	defer second.server.Close()
+
+	pool, err := NewNodePool([]*Iridiumd{first.node, second.node}, PoolConfig{Strategy: HighestHeight})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	first.server.Close()
+	if _, err := pool.GetLastBlockheader(); err != nil {
+		t.Errorf("%sno failover : %v", er, err)
+	}
+	if pool.Status()[0].Healthy {
+		t.Errorf("%sunreachable node still in rotation", er)
+	}
+}
+
+// a request which is not idempotent never reaches a second node, unless the first one was unreachable
+func TestNodePool_NoFailoverOfSubmissions(t *testing.T) {
+	var failing, second int32
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&failing, 1)
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer failingServer.Close()
+	secondServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&second, 1)
+		w.Write([]byte(`{"status": "OK"}`))
+	}))
+	defer secondServer.Close()
+	first, _ := NewIridiumd(failingServer.URL)
+	next, _ := NewIridiumd(secondServer.URL)
+
+	pool, err := NewNodePool([]*Iridiumd{first, next}, PoolConfig{Strategy: HighestHeight})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if err := pool.CallHTTP(context.Background(), "sendrawtransaction", nil, nil); err == nil || failing != 1 || second != 0 {
+		t.Errorf("%ssendrawtransaction sent %d and %d times : %v", er, failing, second, err)
+	}
+
+	// read methods fail over
+	pool, _ = NewNodePool([]*Iridiumd{first, next}, PoolConfig{Strategy: HighestHeight})
+	if err := pool.CallHTTP(context.Background(), "getheight", nil, nil); err != nil || second != 1 {
+		t.Errorf("%sgetheight not failed over : %v", er, err)
+	}
+
+	// an unreachable node never received the request
+	down, _ := NewIridiumd("http://127.0.0.1:1")
+	pool, _ = NewNodePool([]*Iridiumd{down, next}, PoolConfig{Strategy: HighestHeight})
+	if err := pool.CallHTTP(context.Background(), "sendrawtransaction", nil, nil); err != nil || second != 2 {
+		t.Errorf("%ssendrawtransaction not sent to the reachable node : %v", er, err)
+	}
+	t.Logf("%ssubmissions sent once", ok)
+}
+
+func TestNodePool_Strategies(t *testing.T) {
+	low := newPoolTestNode(t, 1000, true)
+	defer low.server.Close()
+	high := newPoolTestNode(t, 1001, true)
+	defer high.server.Close()
+
+	// round robin spreads the calls
+	pool, err := NewNodePool([]*Iridiumd{low.node, high.node}, PoolConfig{MaxHeightLag: 5})
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	for i := 0; i < 4; i++ {
+		pool.BlockCount()
+	}
+	if low.calls != 2 || high.calls != 2 {
+		t.Errorf("%sround robin calls : %d and %d", er, low.calls, high.calls)
+	}
+
+	// highest height first
+	if pool, err = NewNodePool([]*Iridiumd{low.node, high.node}, PoolConfig{Strategy: HighestHeight, MaxHeightLag: 5}); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	pool.CheckHealth(context.Background())
+	if count, err := pool.BlockCount(); err != nil || count != 1001 {
+		t.Errorf("%shighest height node not chosen : %d, %v", er, count, err)
+	}
+
+	if _, err := NewNodePool(nil, PoolConfig{}); err != ErrNoNode {
+		t.Errorf("%swant %v, got %v", er, ErrNoNode, err)
+	}
+}
+
+func TestNodePool_Busy(t *testing.T) {
+	busy, ready := iridiumdtest.NewServer(), iridiumdtest.NewServer()
+	defer busy.Close()
+	defer ready.Close()
+	first, _ := NewIridiumd(busy.URL)
+	second, _ := NewIridiumd(ready.URL)
+
+	// the core busy error of json_rpc, the "BUSY" status of the other endpoints
+	for method, call := range map[string]func(pool *NodePool) error{
+		"getlastblockheader": func(pool *NodePool) error { _, err := pool.LastBlockHeader(); return err },
+		"getheight":          func(pool *NodePool) error { _, err := pool.Height(); return err },
+	} {
+		busy.Inject(iridiumdtest.Fault{Method: method, Busy: true, Count: 1})
+		pool, err := NewNodePool([]*Iridiumd{first, second}, PoolConfig{Strategy: HighestHeight})
+		if err != nil {
+			t.Fatalf("%s %s", er, err)
+		}
+		if err := call(pool); err != nil {
+			t.Errorf("%s%s not failed over : %v", er, method, err)
+		}
+		if status := pool.Status(); status[0].Healthy || !errors.Is(status[0].Err, ErrNodeBusy) {
+			t.Errorf("%sbusy node still in rotation after %s : %+v", er, method, status[0])
+		}
+		if requests := ready.Requests(); len(requests) == 0 || requests[len(requests)-1] != method {
+			t.Errorf("%s%s not sent to the ready node : %v", er, method, requests)
+		}
+	}
+
+	// a busy node didn't run the request, a submission goes to the next node
+	busy.Inject(iridiumdtest.Fault{Method: "submitblock", Busy: true, Count: 1})
+	pool, _ := NewNodePool([]*Iridiumd{first, second}, PoolConfig{Strategy: HighestHeight})
+	pool.SubmitBlock("00")
+	if requests := ready.Requests(); requests[len(requests)-1] != "submitblock" {
+		t.Errorf("%ssubmitblock not sent to the ready node : %v", er, requests)
+	}
+	t.Logf("%sbusy node skipped", ok)
+}
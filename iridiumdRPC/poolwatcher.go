@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transactions pool watcher
+
+package iridiumdRPC
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PoolEventType, kind of transactions pool event
+type PoolEventType int
+
+const (
+	// TxEnteredPool, a transaction is in the pool
+	TxEnteredPool PoolEventType = iota
+	// TxLeftPool, a transaction left the pool, mined in the block at Height
+	TxLeftPool
+	// TxEvicted, a transaction left the pool without being mined
+	TxEvicted
+)
+
+func (eventType PoolEventType) String() string {
+	switch eventType {
+	case TxEnteredPool:
+		return "entered pool"
+	case TxLeftPool:
+		return "left pool"
+	case TxEvicted:
+		return "evicted"
+	}
+	return "unknown event " + strconv.Itoa(int(eventType))
+}
+
+// PoolEvent, a change of the transactions pool, Height and BlockHash are only set for TxLeftPool
+type PoolEvent struct {
+	Type        PoolEventType
+	Transaction PoolTransaction
+	Height      uint32
+	BlockHash   string
+}
+
+/*
+PoolWatcher, polls the transactions pool and reports the transactions entering and leaving it,
+a transaction leaving the pool is looked for in the blocks added since the previous poll to tell if it was mined,
+a watcher isn't safe for concurrent use
+*/
+type PoolWatcher struct {
+	node         *Iridiumd
+	pollInterval time.Duration
+	started      bool
+	height       uint32
+	known        map[string]PoolTransaction
+}
+
+// NewPoolWatcher, returns a watcher of the node pool polling every pollInterval, 5 seconds when 0
+func NewPoolWatcher(node *Iridiumd, pollInterval time.Duration) *PoolWatcher {
+	if pollInterval == 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &PoolWatcher{node: node, pollInterval: pollInterval, known: make(map[string]PoolTransaction)}
+}
+
+/*
+Run, polls the pool every poll interval and sends the events to handle until ctx is done,
+retryable node errors wait for the next poll, other errors and handle errors stop Run
+*/
+func (watcher *PoolWatcher) Run(ctx context.Context, handle func(event PoolEvent) error) error {
+	ticker := time.NewTicker(watcher.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := watcher.Poll(ctx, handle); err != nil && (!IsRetryable(err) || ctx.Err() != nil) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Poll, compares the pool with the previous poll and sends the events to handle,
+the first poll sends TxEnteredPool for every transaction already in the pool,
+an event refused by handle is sent again at the next poll
+*/
+func (watcher *PoolWatcher) Poll(ctx context.Context, handle func(event PoolEvent) error) error {
+	// the height is read before the pool, the next poll looks for the transactions mined above it
+	count, err := watcher.node.BlockCountContext(ctx)
+	if err != nil {
+		return err
+	}
+	transactions, err := watcher.node.TransactionsPoolContext(ctx)
+	if err != nil {
+		return err
+	}
+	top := count - 1
+	if !watcher.started {
+		watcher.height, watcher.started = top, true
+	}
+
+	inPool := make(map[string]bool, len(transactions))
+	for _, transaction := range transactions {
+		inPool[transaction.Hash] = true
+	}
+	var gone []string
+	for hash := range watcher.known {
+		if !inPool[hash] {
+			gone = append(gone, hash)
+		}
+	}
+	sort.Strings(gone)
+
+	// a transaction missing from the pool was mined under the height read after it
+	var mined map[string]BlockShort
+	if len(gone) > 0 {
+		if count, err = watcher.node.BlockCountContext(ctx); err != nil {
+			return err
+		}
+		if count-1 > watcher.height {
+			if mined, err = watcher.minedSince(ctx, count-1); err != nil {
+				return err
+			}
+		}
+	}
+	for _, hash := range gone {
+		event := PoolEvent{Type: TxEvicted, Transaction: watcher.known[hash]}
+		if block, found := mined[hash]; found {
+			event.Type, event.Height, event.BlockHash = TxLeftPool, block.Height, block.Hash
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+		delete(watcher.known, hash)
+	}
+
+	for _, transaction := range transactions {
+		if _, found := watcher.known[transaction.Hash]; found {
+			continue
+		}
+		if err := handle(PoolEvent{Type: TxEnteredPool, Transaction: transaction}); err != nil {
+			return err
+		}
+		watcher.known[transaction.Hash] = transaction
+	}
+	watcher.height = top
+	return nil
+}
+
+// transactions of the blocks above the previous poll height up to top, by hash
+func (watcher *PoolWatcher) minedSince(ctx context.Context, top uint32) (map[string]BlockShort, error) {
+	mined := make(map[string]BlockShort)
+	blocks := watcher.node.Blocks(ctx, watcher.height+1, top).WithDetails(4)
+	for blocks.Next() {
+		for _, transaction := range blocks.Details().Transactions {
+			mined[transaction.Hash] = blocks.Block()
+		}
+	}
+	return mined, blocks.Err()
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transactions pool watcher tests
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fake node with a transactions pool and blocks holding transactions
+type testMempool struct {
+	mu     sync.Mutex
+	pool   []string
+	blocks [][]string
+	// called with each method before it is answered
+	onRequest func(method string)
+}
+
+// mines the pool transactions of hashes in a new block, and evicts evicted
+func (mempool *testMempool) mine(hashes []string, evicted ...string) {
+	mempool.mu.Lock()
+	defer mempool.mu.Unlock()
+	mempool.blocks = append(mempool.blocks, hashes)
+	left := make(map[string]bool)
+	for _, hash := range append(hashes, evicted...) {
+		left[hash] = true
+	}
+	var pool []string
+	for _, hash := range mempool.pool {
+		if !left[hash] {
+			pool = append(pool, hash)
+		}
+	}
+	mempool.pool = pool
+}
+
+func (mempool *testMempool) add(hashes ...string) {
+	mempool.mu.Lock()
+	defer mempool.mu.Unlock()
+	mempool.pool = append(mempool.pool, hashes...)
+}
+
+func newTestMempool(t *testing.T) (*testMempool, *Iridiumd, *httptest.Server) {
+	mempool := &testMempool{blocks: make([][]string, 10)}
+	hash := func(height int) string { return fmt.Sprintf("block%08d", height) }
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		var request testRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		if mempool.onRequest != nil {
+			mempool.onRequest(request.Method)
+		}
+		mempool.mu.Lock()
+		defer mempool.mu.Unlock()
+		result := map[string]interface{}{"status": "OK"}
+		switch request.Method {
+		case "getblockcount":
+			result["count"] = len(mempool.blocks)
+		case "f_on_transactions_pool_json":
+			var transactions []map[string]interface{}
+			for _, hash := range mempool.pool {
+				transactions = append(transactions, map[string]interface{}{"hash": hash, "fee": 100})
+			}
+			result["transactions"] = transactions
+		case "f_blocks_list_json":
+			var blocks []map[string]interface{}
+			for height := int(request.Params["height"].(float64)); height >= 0; height-- {
+				blocks = append(blocks, map[string]interface{}{"height": height, "hash": hash(height)})
+			}
+			result["blocks"] = blocks
+		case "f_block_json":
+			var height int
+			fmt.Sscanf(request.Params["hash"].(string), "block%08d", &height)
+			var transactions []map[string]interface{}
+			for _, hash := range mempool.blocks[height] {
+				transactions = append(transactions, map[string]interface{}{"hash": hash})
+			}
+			result["block"] = map[string]interface{}{"height": height, "hash": hash(height), "transactions": transactions}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	})
+	return mempool, node, server
+}
+
+// polls and returns the events as "type hash height"
+func pollPoolEvents(t *testing.T, watcher *PoolWatcher) []string {
+	var events []string
+	err := watcher.Poll(context.Background(), func(event PoolEvent) error {
+		events = append(events, fmt.Sprintf("%s %s %d", event.Type, event.Transaction.Hash, event.Height))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%sPoll : %v", er, err)
+	}
+	return events
+}
+
+func TestPoolWatcher(t *testing.T) {
+	mempool, node, server := newTestMempool(t)
+	defer server.Close()
+	watcher := NewPoolWatcher(node, 0)
+
+	mempool.add("tx1", "tx2")
+	checkEvents(t, pollPoolEvents(t, watcher), "entered pool tx1 0", "entered pool tx2 0")
+	checkEvents(t, pollPoolEvents(t, watcher))
+
+	// tx1 mined 2 blocks later, tx2 evicted, tx3 arrives
+	mempool.mine(nil)
+	mempool.mine([]string{"coinbase", "tx1"}, "tx2")
+	mempool.add("tx3")
+	checkEvents(t, pollPoolEvents(t, watcher), "left pool tx1 11", "evicted tx2 0", "entered pool tx3 0")
+	t.Logf("%spool changes reported", ok)
+
+	// a refused event is sent again
+	mempool.mine([]string{"tx3"})
+	failure := errors.New("handler failure")
+	if err := watcher.Poll(context.Background(), func(PoolEvent) error { return failure }); err != failure {
+		t.Fatalf("%swant %v, got %v", er, failure, err)
+	}
+	checkEvents(t, pollPoolEvents(t, watcher), "left pool tx3 12")
+}
+
+func TestPoolWatcher_MinedBetweenReads(t *testing.T) {
+	mempool, node, server := newTestMempool(t)
+	defer server.Close()
+	watcher := NewPoolWatcher(node, 0)
+	mempool.add("tx1")
+	checkEvents(t, pollPoolEvents(t, watcher), "entered pool tx1 0")
+
+	// a block lands between the first two reads of the poll
+	requests := 0
+	mempool.onRequest = func(string) {
+		if requests++; requests == 2 {
+			mempool.mine([]string{"tx1"})
+		}
+	}
+	events := pollPoolEvents(t, watcher)
+	mempool.onRequest = nil
+	events = append(events, pollPoolEvents(t, watcher)...)
+	checkEvents(t, events, "left pool tx1 10")
+	t.Logf("%s%q", ok, events)
+}
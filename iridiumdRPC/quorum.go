@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium nodes quorum reads and fork detection
+
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxForkDepth, blocks searched back for the last height where forked nodes agree
+const DefaultMaxForkDepth = 100
+
+// ErrNoQuorum, not enough nodes answered the same block
+var ErrNoQuorum = errors.New("no quorum")
+
+/*
+ForkDetected, nodes disagree on the block hash at Height, Hashes lists the nodes behind each competing hash,
+LastAgreedHeight is the last height where they agree, when Agreed is false no agreement was found
+within MaxForkDepth blocks, it is an event sent to OnFork and the error returned when no quorum is reached
+*/
+type ForkDetected struct {
+	Height           uint32
+	Hashes           map[string][]*Iridiumd
+	LastAgreedHeight uint32
+	Agreed           bool
+}
+
+func (fork *ForkDetected) Error() string {
+	hashes := make([]string, 0, len(fork.Hashes))
+	for hash, nodes := range fork.Hashes {
+		hashes = append(hashes, hash+" ("+strconv.Itoa(len(nodes))+" nodes)")
+	}
+	sort.Strings(hashes)
+	message := "fork detected at height " + strconv.FormatUint(uint64(fork.Height), 10) + " : " + strings.Join(hashes, ", ")
+	if fork.Agreed {
+		message += ", last agreed height " + strconv.FormatUint(uint64(fork.LastAgreedHeight), 10)
+	}
+	return message
+}
+
+/*
+Quorum, reads block headers from several nodes and returns a header only when Required nodes agree on its hash,
+and no other hash is shared by as many nodes, NewQuorum requires a majority so that two hashes can't both reach it
+when nodes disagree at a height, OnFork is called with a ForkDetected event, even if the quorum is reached
+*/
+type Quorum struct {
+	Nodes    []*Iridiumd
+	Required int
+	// OnFork, called when nodes disagree, may be nil
+	OnFork func(fork *ForkDetected)
+	// MaxForkDepth, DefaultMaxForkDepth when 0
+	MaxForkDepth uint32
+}
+
+// NewQuorum, returns a quorum of nodes where required nodes, a majority of them, must agree
+func NewQuorum(nodes []*Iridiumd, required int) (*Quorum, error) {
+	if required*2 <= len(nodes) || required > len(nodes) {
+		return nil, errors.New("required agreeing nodes must be a majority, between " + strconv.Itoa(len(nodes)/2+1) +
+			" and " + strconv.Itoa(len(nodes)))
+	}
+	return &Quorum{Nodes: nodes, Required: required}, nil
+}
+
+// answer of a node
+type nodeHeader struct {
+	node   *Iridiumd
+	header *BlockHeader
+	err    error
+}
+
+// calls get on every node concurrently
+func (quorum *Quorum) query(ctx context.Context, nodes []*Iridiumd, get func(node *Iridiumd) (*BlockHeader, error)) []nodeHeader {
+	answers := make([]nodeHeader, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(answer *nodeHeader, node *Iridiumd) {
+			defer wg.Done()
+			answer.node = node
+			answer.header, answer.err = get(node)
+		}(&answers[i], node)
+	}
+	wg.Wait()
+	return answers
+}
+
+// BlockHeaderByHeight, returns the block header at height once Required nodes agree on its hash
+func (quorum *Quorum) BlockHeaderByHeight(ctx context.Context, height uint32) (*BlockHeader, error) {
+	answers := quorum.query(ctx, quorum.Nodes, func(node *Iridiumd) (*BlockHeader, error) {
+		return node.BlockHeaderByHeightContext(ctx, height)
+	})
+
+	hashes := make(map[string][]*Iridiumd)
+	headers := make(map[string]*BlockHeader)
+	var errs []string
+	for _, answer := range answers {
+		if answer.err != nil {
+			errs = append(errs, answer.err.Error())
+			continue
+		}
+		hashes[answer.header.Hash] = append(hashes[answer.header.Hash], answer.node)
+		headers[answer.header.Hash] = answer.header
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var fork *ForkDetected
+	if len(hashes) > 1 {
+		fork = &ForkDetected{Height: height, Hashes: hashes}
+		fork.LastAgreedHeight, fork.Agreed = quorum.lastAgreedHeight(ctx, hashes, height)
+		if quorum.OnFork != nil {
+			quorum.OnFork(fork)
+		}
+	}
+	// the most shared hash wins, a tie is a fork even when both hashes reach Required
+	if hash, tie := mostShared(hashes); !tie && len(hashes[hash]) >= quorum.Required {
+		return headers[hash], nil
+	}
+	if fork != nil {
+		return nil, fork
+	}
+	return nil, fmt.Errorf("%w at height %d : %d of %d required nodes agree, errors : %s",
+		ErrNoQuorum, height, agreeing(hashes), quorum.Required, strings.Join(errs, ", "))
+}
+
+/*
+LastBlockHeader, returns the header of the highest block reached by Required nodes, once they agree on its hash
+*/
+func (quorum *Quorum) LastBlockHeader(ctx context.Context) (*BlockHeader, error) {
+	answers := quorum.query(ctx, quorum.Nodes, func(node *Iridiumd) (*BlockHeader, error) {
+		return node.LastBlockHeaderContext(ctx)
+	})
+	var heights []uint32
+	for _, answer := range answers {
+		if answer.err == nil {
+			heights = append(heights, answer.header.Height)
+		}
+	}
+	if len(heights) < quorum.Required {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w : %d of %d required nodes answered", ErrNoQuorum, len(heights), quorum.Required)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	return quorum.BlockHeaderByHeight(ctx, heights[quorum.Required-1])
+}
+
+// hash shared by the most nodes, true when another hash is shared by as many nodes
+func mostShared(hashes map[string][]*Iridiumd) (string, bool) {
+	var best string
+	tie := false
+	for hash, nodes := range hashes {
+		switch {
+		case len(nodes) > len(hashes[best]):
+			best, tie = hash, false
+		case len(nodes) == len(hashes[best]):
+			tie = true
+		}
+	}
+	return best, tie
+}
+
+// number of nodes behind the most shared hash
+func agreeing(hashes map[string][]*Iridiumd) int {
+	best := 0
+	for _, nodes := range hashes {
+		if len(nodes) > best {
+			best = len(nodes)
+		}
+	}
+	return best
+}
+
+/*
+binary search of the last height below height where one node of each competing hash agree,
+chains share their blocks up to the fork point and differ above it
+*/
+func (quorum *Quorum) lastAgreedHeight(ctx context.Context, hashes map[string][]*Iridiumd, height uint32) (uint32, bool) {
+	if height == 0 {
+		return 0, false
+	}
+	representatives := make([]*Iridiumd, 0, len(hashes))
+	for _, nodes := range hashes {
+		representatives = append(representatives, nodes[0])
+	}
+	depth := quorum.MaxForkDepth
+	if depth == 0 {
+		depth = DefaultMaxForkDepth
+	}
+	low := uint32(0)
+	if height > depth {
+		low = height - depth
+	}
+
+	agreeAt := func(h uint32) bool {
+		answers := quorum.query(ctx, representatives, func(node *Iridiumd) (*BlockHeader, error) {
+			return node.BlockHeaderByHeightContext(ctx, h)
+		})
+		for _, answer := range answers {
+			if answer.err != nil || answer.header.Hash != answers[0].header.Hash {
+				return false
+			}
+		}
+		return true
+	}
+	if !agreeAt(low) {
+		return 0, false
+	}
+	// agreement at low, disagreement at height
+	high := height
+	for high-low > 1 {
+		middle := low + (high-low)/2
+		if agreeAt(middle) {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return low, true
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium nodes quorum tests
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fake node of a chain named branch above forkHeight, answering block headers by height and the last one
+func newChainNode(t *testing.T, branch string, forkHeight uint32, top uint32) (*Iridiumd, *httptest.Server) {
+	hash := func(height uint32) string {
+		if height > forkHeight {
+			return fmt.Sprintf("%s%08d", branch, height)
+		}
+		return fmt.Sprintf("main%08d", height)
+	}
+	return newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		var request testRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		height := top
+		if request.Method == "getblockheaderbyheight" {
+			height = uint32(request.Params["height"].(float64))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result": map[string]interface{}{
+				"block_header": map[string]interface{}{"height": height, "hash": hash(height), "prev_hash": hash(height - 1)},
+				"status":       "OK",
+			},
+		})
+	})
+}
+
+func TestQuorum_Agree(t *testing.T) {
+	node1, server1 := newChainNode(t, "main", 1000, 500)
+	defer server1.Close()
+	node2, server2 := newChainNode(t, "main", 1000, 502)
+	defer server2.Close()
+	node3, server3 := newChainNode(t, "alt", 400, 505)
+	defer server3.Close()
+
+	quorum, err := NewQuorum([]*Iridiumd{node1, node2, node3}, 2)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	var forks []*ForkDetected
+	quorum.OnFork = func(fork *ForkDetected) { forks = append(forks, fork) }
+
+	// below the fork, every node agrees
+	header, err := quorum.BlockHeaderByHeight(context.Background(), 300)
+	if err != nil || header.Hash != "main00000300" || len(forks) != 0 {
+		t.Errorf("%sBlockHeaderByHeight returns %+v, %v, forks %v", er, header, err, forks)
+	}
+
+	// above the fork, the quorum is reached, the fork is reported
+	header, err = quorum.BlockHeaderByHeight(context.Background(), 450)
+	if err != nil || header.Hash != "main00000450" {
+		t.Errorf("%sBlockHeaderByHeight returns %+v, %v", er, header, err)
+	}
+	if len(forks) != 1 || forks[0].Height != 450 || !forks[0].Agreed || forks[0].LastAgreedHeight != 400 || len(forks[0].Hashes) != 2 {
+		t.Fatalf("%sfork events %+v", er, forks)
+	}
+	t.Logf("%s%v", ok, forks[0])
+
+	// last header reached by 2 nodes
+	header, err = quorum.LastBlockHeader(context.Background())
+	if err != nil || header.Height != 502 || header.Hash != "main00000502" {
+		t.Errorf("%sLastBlockHeader returns %+v, %v", er, header, err)
+	}
+}
+
+func TestQuorum_Fork(t *testing.T) {
+	node1, server1 := newChainNode(t, "main", 1000, 500)
+	defer server1.Close()
+	node2, server2 := newChainNode(t, "alt", 480, 500)
+	defer server2.Close()
+	down, server3 := newChainNode(t, "main", 1000, 500)
+	server3.Close()
+
+	quorum, err := NewQuorum([]*Iridiumd{node1, node2, down}, 2)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	_, err = quorum.BlockHeaderByHeight(context.Background(), 490)
+	var fork *ForkDetected
+	if !errors.As(err, &fork) || fork.LastAgreedHeight != 480 {
+		t.Fatalf("%swant *ForkDetected at 480, got %v", er, err)
+	}
+	t.Logf("%s%v", ok, err)
+
+	// below the fork, only 2 nodes answer
+	if _, err = quorum.BlockHeaderByHeight(context.Background(), 100); err != nil {
+		t.Errorf("%s %s", er, err)
+	}
+	quorum.Required = 3
+	if _, err = quorum.BlockHeaderByHeight(context.Background(), 100); !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("%swant %v, got %v", er, ErrNoQuorum, err)
+	}
+
+	if _, err := NewQuorum([]*Iridiumd{node1}, 2); err == nil {
+		t.Errorf("%sunreachable quorum should fail", er)
+	}
+}
+
+// two hashes shared by Required nodes each are a fork, whatever the order of the answers
+func TestQuorum_Tie(t *testing.T) {
+	var nodes []*Iridiumd
+	for _, branch := range []string{"main", "main", "alt", "alt"} {
+		node, server := newChainNode(t, branch, 400, 500)
+		defer server.Close()
+		nodes = append(nodes, node)
+	}
+	if _, err := NewQuorum(nodes, 2); err == nil {
+		t.Errorf("%s2 of 4 nodes accepted as a quorum", er)
+	}
+
+	quorum := &Quorum{Nodes: nodes, Required: 2}
+	for i := 0; i < 10; i++ {
+		var fork *ForkDetected
+		if header, err := quorum.BlockHeaderByHeight(context.Background(), 450); !errors.As(err, &fork) {
+			t.Fatalf("%swant *ForkDetected, got %+v, %v", er, header, err)
+		}
+	}
+	t.Logf("%stie reported as a fork", ok)
+}
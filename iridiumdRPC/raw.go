@@ -0,0 +1,355 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API, map responses of the previous versions, kept for compatibility
+
+package iridiumdRPC
+
+import "context"
+
+// node GET methods returning the decoded JSON as a map
+
+/*
+/getheight, returns current node height, current network height and status
+output example : map[height:357588 network_height:357588 status:OK]
+
+Deprecated: use Height, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetHeight() (map[string]interface{}, error) {
+	return node.GetHeightContext(context.Background())
+}
+
+// GetHeightContext, same as GetHeight, the context cancels the request
+//
+// Deprecated: use HeightContext
+func (node *Iridiumd) GetHeightContext(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := node.makeGetRequest(ctx, "getheight", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+/getinfo, returns global node informations
+output example : map[alt_blocks_count:0 difficulty:2000000000 grey_peerlist_size:1480 height:357588 incoming_connections_count:14 last_known_block_index:357587 outgoing_connections_count:8 status:OK synced:true tx_count:532754 tx_pool_size:26 version:5.0.0 (0f3cc89) Release white_peerlist_size:108]
+
+Deprecated: use Info, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetInfo() (map[string]interface{}, error) {
+	return node.GetInfoContext(context.Background())
+}
+
+// GetInfoContext, same as GetInfo, the context cancels the request
+//
+// Deprecated: use InfoContext
+func (node *Iridiumd) GetInfoContext(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := node.makeGetRequest(ctx, "getinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+/gettransactions, returns found or not tx and node status
+input params : txs_hashes []string
+output example: map[missed_tx:[] status:OK txs_as_hex:[]
+
+Deprecated: use Transactions, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetTransactions(txsHashes []string) (map[string]interface{}, error) {
+	return node.GetTransactionsContext(context.Background(), txsHashes)
+}
+
+// GetTransactionsContext, same as GetTransactions, the context cancels the request
+//
+// Deprecated: use TransactionsContext
+func (node *Iridiumd) GetTransactionsContext(ctx context.Context, txsHashes []string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["txs_hashes"] = txsHashes
+	resp, err := node.makeGetRequest(ctx, "gettransactions", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+/get_generated_coins, returns status and current circulating coins * denomination unit : 100000000
+output example :  map[alreadyGeneratedCoins:1860645432419705 status:OK]
+this mean : 1860645432419705/100000000 = 18 606 454,3241971 IRD
+
+Deprecated: use GeneratedCoins, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetGeneratedCoins() (map[string]interface{}, error) {
+	return node.GetGeneratedCoinsContext(context.Background())
+}
+
+// GetGeneratedCoinsContext, same as GetGeneratedCoins, the context cancels the request
+//
+// Deprecated: use GeneratedCoinsContext
+func (node *Iridiumd) GetGeneratedCoinsContext(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := node.makeGetRequest(ctx, "get_generated_coins", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+// POST methods returning the whole JSON RPC envelope as a map
+
+/*
+getblockcount, returns current height (including current mined block),
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+output :  map[jsonrpc:2.0 result:map[count:357655 status:OK]]
+or with id : map[id:withID jsonrpc:2.0 result:map[count:357655 status:OK]]
+
+Deprecated: use BlockCount, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetBlockCount(id ...string) (map[string]interface{}, error) {
+	return node.GetBlockCountContext(context.Background(), id...)
+}
+
+// GetBlockCountContext, same as GetBlockCount, the context cancels the request
+//
+// Deprecated: use BlockCountContext
+func (node *Iridiumd) GetBlockCountContext(ctx context.Context, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "getblockcount", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+getcurrencyid, returns genesis block hash
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+output : map[jsonrpc:2.0 result:map[currency_id_blob:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43]]
+or with id : map[id:withID jsonrpc:2.0 result:map[currency_id_blob:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43]]
+
+Deprecated: use CurrencyID, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetCurrencyid(id ...string) (map[string]interface{}, error) {
+	return node.GetCurrencyidContext(context.Background(), id...)
+}
+
+// GetCurrencyidContext, same as GetCurrencyid, the context cancels the request
+//
+// Deprecated: use CurrencyIDContext
+func (node *Iridiumd) GetCurrencyidContext(ctx context.Context, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "getcurrencyid", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+getlastblockheader, last mined block header
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+output :  map[jsonrpc:2.0 result:map[block_header:map[depth:0 difficulty:1750000000 hash:982addbdf1dc687e886baffcbdf6c29fabab8269787980991ee1c34242433cdd height:357765 major_version:5 minor_version:0 nonce:31207 orphan_status:false prev_hash:3b7ca9fdf86ad62a7411827299b4028e5d2701cfa3ccae1a3390faebecfa5b42 reward:2437467306 timestamp:1567538093] status:OK]]
+or with id : map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:0 difficulty:1750000000 hash:982addbdf1dc687e886baffcbdf6c29fabab8269787980991ee1c34242433cdd height:357765 major_version:5 minor_version:0 nonce:31207 orphan_status:false prev_hash:3b7ca9fdf86ad62a7411827299b4028e5d2701cfa3ccae1a3390faebecfa5b42 reward:2437467306 timestamp:1567538093] status:OK]]
+
+Deprecated: use LastBlockHeader, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetLastBlockheader(id ...string) (map[string]interface{}, error) {
+	return node.GetLastBlockheaderContext(context.Background(), id...)
+}
+
+// GetLastBlockheaderContext, same as GetLastBlockheader, the context cancels the request
+//
+// Deprecated: use LastBlockHeaderContext
+func (node *Iridiumd) GetLastBlockheaderContext(ctx context.Context, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "getlastblockheader", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+getblockheaderbyhash, returns the block header by hash
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : hash string
+output : map[jsonrpc:2.0 result:map[block_header:map[depth:357767 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9536743164 timestamp:0] status:OK]]
+or with id :  map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:357767 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9536743164 timestamp:0] status:OK]]
+
+Deprecated: use BlockHeaderByHash, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetBlockHeaderByHash(hash string, id ...string) (map[string]interface{}, error) {
+	return node.GetBlockHeaderByHashContext(context.Background(), hash, id...)
+}
+
+// GetBlockHeaderByHashContext, same as GetBlockHeaderByHash, the context cancels the request
+//
+// Deprecated: use BlockHeaderByHashContext
+func (node *Iridiumd) GetBlockHeaderByHashContext(ctx context.Context, hash string, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["hash"] = hash
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "getblockheaderbyhash", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+getblockheaderbyheight, returns the block header at desired height
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : height uint32
+output : map[jsonrpc:2.0 result:map[block_header:map[depth:357672 difficulty:1 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9536743164 timestamp:0] status:OK]]
+or with id : map[id:withID jsonrpc:2.0 result:map[block_header:map[depth:357572 difficulty:10857 hash:823f7bf3e6ccf9818c7b58aebebde7bf79f25b5118dc02233ac38333340ed894 height:100 major_version:1 minor_version:0 nonce:429672923 orphan_status:false prev_hash:3f726a8f697c1cc03f54bf0f1d609ef677b7b8597f24a67aa733bd8a810f023c reward:9533105872 timestamp:1504560271] status:OK]]
+
+Deprecated: use BlockHeaderByHeight, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetBlockHeaderByHeight(height uint32, id ...string) (map[string]interface{}, error) {
+	return node.GetBlockHeaderByHeightContext(context.Background(), height, id...)
+}
+
+// GetBlockHeaderByHeightContext, same as GetBlockHeaderByHeight, the context cancels the request
+//
+// Deprecated: use BlockHeaderByHeightContext
+func (node *Iridiumd) GetBlockHeaderByHeightContext(ctx context.Context, height uint32, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["height"] = height
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "getblockheaderbyheight", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+f_blocks_list_json, returns 30 blocks headers from desired height to height - 30
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : height uint32
+output : map[jsonrpc:2.0 result:map[blocks:[map[cumul_size:410 difficulty:9997 hash:f8efb98beee5930a403b16a12bb212f88a06c84dadb330090eb0e22528b3c90f height:30 reward:9535651830 timestamp:1504551188 tx_count:1], etc...
+or with id : map[id:withID jsonrpc:2.0 result:map[blocks:[map[cumul_size:408 difficulty:10857 hash:823f7bf3e6ccf9818c7b58aebebde7bf79f25b5118dc02233ac38333340ed894 height:100 reward:9533105872 timestamp:1504560271 tx_count:1], etc...
+
+Deprecated: use BlocksList, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetBlocksList(height uint32, id ...string) (map[string]interface{}, error) {
+	return node.GetBlocksListContext(context.Background(), height, id...)
+}
+
+// GetBlocksListContext, same as GetBlocksList, the context cancels the request
+//
+// Deprecated: use BlocksListContext
+func (node *Iridiumd) GetBlocksListContext(ctx context.Context, height uint32, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["height"] = height
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "f_blocks_list_json", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+f_block_json, returns block detail at  desired hash
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : hash string
+output :  map[jsonrpc:2.0 result:map[block:map[alreadyGeneratedCoins:9536743164 alreadyGeneratedTransactions:1 baseReward:9536743164 blockSize:118 depth:357785 difficulty:1 effectiveSizeMedian:20000 hash:9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43 height:0 major_version:1 minor_version:0 nonce:70 orphan_status:false penalty:0 prev_hash:0000000000000000000000000000000000000000000000000000000000000000 reward:9536743164 sizeMedian:0 timestamp:0 totalFeeAmount:0 transactions:[map[amount_out:9536743164 fee:0 hash:f3fe271b4edceebf60a29d535a8dec957809baf4c69549a09ae113eb88a5f1ad size:78]] transactionsCumulativeSize:78] status:OK]]
+or with id : same with [id:withID...
+
+Deprecated: use BlockDetails, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetBlockDetails(hash string, id ...string) (map[string]interface{}, error) {
+	return node.GetBlockDetailsContext(context.Background(), hash, id...)
+}
+
+// GetBlockDetailsContext, same as GetBlockDetails, the context cancels the request
+//
+// Deprecated: use BlockDetailsContext
+func (node *Iridiumd) GetBlockDetailsContext(ctx context.Context, hash string, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["hash"] = hash
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "f_block_json", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+f_transaction_json, returns block detail at  desired hash
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+input : hash string
+output :  map[jsonrpc:2.0 result:map[block:map[cumul_size:8430 difficulty:475677472 hash:f0c002e703d8dc19f6a5ca844805015213f223f3a52a258d3fa22a69e8177213 height:357782 reward:385792735 timestamp:1567540598 tx_count:7] status:OK tx:map[extra:01e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000 unlock_time:357802 version:1 vin:[map[type:ff value:map[height:357782]]] vout:[map[amount:241 target:map[data:map[key:5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58] type:02]] map[amount:9000 target:map[data:map[key:4b40a1f97a411881321022de6a8f532f30c4c404c39c345e26250e162935af79] type:02]] map[amount:600000 target:map[data:map[key:c7c6b07057750fff3ba1520ffbd71aeeb080f796315764ac2021f7f28d064b42] type:02]] map[amount:7000000 target:map[data:map[key:638e1cc733f3566091bfbf696c680264a53dec7e75f82b5d09bd96b7e0e788d0] type:02]] map[amount:30000000 target:map[data:map[key:fc0b8cb0e7bf0a7fd8bee0765add136a03b72ddca9c5bd83b755ac3900cc4822] type:02]] map[amount:400000000 target:map[data:map[key:e9caba0c8f62238ebe81997faa69212c26a21d26199f2c2ba3aa125e3ec33c2c] type:02]] map[amount:2000000000 target:map[data:map[key:d1592617829f57545c0929e4cb286c019da0dd1d1910b7531a25a396466b0bda] type:02]]]] txDetails:map[amount_out:2437609241 fee:0 hash:ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2 mixin:0 paymentId: size:319]]]
+or with id : same with [id:withID...
+
+Deprecated: use TransactionDetails, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetTransactionDetails(hash string, id ...string) (map[string]interface{}, error) {
+	return node.GetTransactionDetailsContext(context.Background(), hash, id...)
+}
+
+// GetTransactionDetailsContext, same as GetTransactionDetails, the context cancels the request
+//
+// Deprecated: use TransactionDetailsContext
+func (node *Iridiumd) GetTransactionDetailsContext(ctx context.Context, hash string, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	payload["hash"] = hash
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "f_transaction_json", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
+
+/*
+f_on_transactions_pool_json, get mem pool status
+id is optional, a unique id is generated otherwise, request id and response id are always compared
+output :  7c0902baf3e50f7f3a202bdafe5091a1eb6befb9eedd89b6cbfa2b051450a73a
+or with id : map[id:withID jsonrpc:2.0 ...
+
+Deprecated: use TransactionsPool, which decodes the response into a typed value
+*/
+func (node *Iridiumd) GetTransactionsPool(id ...string) (map[string]interface{}, error) {
+	return node.GetTransactionsPoolContext(context.Background(), id...)
+}
+
+// GetTransactionsPoolContext, same as GetTransactionsPool, the context cancels the request
+//
+// Deprecated: use TransactionsPoolContext
+func (node *Iridiumd) GetTransactionsPoolContext(ctx context.Context, id ...string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if len(id) != 0 {
+		payload["id"] = id[0]
+	}
+	resp, err := node.makePostRequest(ctx, "f_on_transactions_pool_json", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRaw(resp)
+}
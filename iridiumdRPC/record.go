@@ -0,0 +1,286 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node traffic recording and replay, for deterministic tests
+
+package iridiumdRPC
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoRecording, a replayed request has no recorded exchange
+var ErrNoRecording = errors.New("no recorded exchange")
+
+// a recorded exchange, one golden file
+type exchange struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	// IDs, json_rpc ids of the recorded request, rewritten on replay
+	IDs          []interface{}   `json:"ids,omitempty"`
+	StatusCode   int             `json:"status_code"`
+	Response     json.RawMessage `json:"response,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+}
+
+// a json_rpc call of a request, the id is left out of the keys
+type recordedCall struct {
+	ID     interface{}     `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+/*
+returns the key of a request : the json_rpc method, "batch" or the endpoint path, and the params without ids,
+the golden file name is the method followed by a hash of the params
+*/
+func exchangeKey(r *http.Request, body []byte) (method string, params json.RawMessage, ids []interface{}, err error) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if path != "json_rpc" {
+		if len(bytes.TrimSpace(body)) == 0 {
+			return path, nil, nil, nil
+		}
+		params, err = canonicalJSON(body)
+		return path, params, nil, err
+	}
+
+	var calls []recordedCall
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	if batch {
+		err = json.Unmarshal(body, &calls)
+	} else {
+		calls = make([]recordedCall, 1)
+		err = json.Unmarshal(body, &calls[0])
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+	keys := make([]recordedCall, len(calls))
+	for i, call := range calls {
+		ids = append(ids, call.ID)
+		keys[i].Method = call.Method
+		if keys[i].Params, err = canonicalJSON(call.Params); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if !batch {
+		return calls[0].Method, keys[0].Params, ids, nil
+	}
+	params, err = json.Marshal(keys)
+	return "batch", params, ids, err
+}
+
+// JSON with sorted object keys, numbers kept as written
+func canonicalJSON(raw []byte) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func exchangeFile(dir string, method string, params json.RawMessage) string {
+	sum := sha256.Sum256(params)
+	return filepath.Join(dir, method+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// reads the request body and restores it for the next round tripper
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder, round tripper sending the requests to next and writing each exchange to a golden file of Dir
+type Recorder struct {
+	Dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecorder, returns a recorder of the exchanges with next into dir, next is http.DefaultTransport when nil
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{Dir: dir, next: next}
+}
+
+// RoundTrip, sends the request and records the exchange, the last exchange of a key is kept
+func (recorder *Recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	method, params, ids, err := exchangeKey(r, body)
+	if err != nil {
+		return nil, fmt.Errorf("recorder : %w", err)
+	}
+	response, err := recorder.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	record := exchange{Method: method, Params: params, IDs: ids, StatusCode: response.StatusCode}
+	if json.Valid(responseBody) {
+		record.Response = responseBody
+	} else {
+		record.ResponseText = string(responseBody)
+	}
+	golden, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if err := os.MkdirAll(recorder.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(exchangeFile(recorder.Dir, method, params), append(golden, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+/*
+Replayer, round tripper answering the requests from the golden files of Dir, without network,
+the json_rpc ids of the responses are replaced by the request ones,
+a request without golden file fails with ErrNoRecording
+*/
+type Replayer struct {
+	Dir string
+}
+
+// NewReplayer, returns a replayer of the exchanges recorded into dir
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{Dir: dir}
+}
+
+// RoundTrip, answers the request from its golden file
+func (replayer *Replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	method, params, ids, err := exchangeKey(r, body)
+	if err != nil {
+		return nil, fmt.Errorf("replayer : %w", err)
+	}
+	file := exchangeFile(replayer.Dir, method, params)
+	golden, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w for %s %s, expected in %s", ErrNoRecording, method, params, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record exchange
+	if err := json.Unmarshal(golden, &record); err != nil {
+		return nil, fmt.Errorf("replayer : %s : %w", file, err)
+	}
+
+	responseBody := []byte(record.ResponseText)
+	if record.Response != nil {
+		if responseBody, err = replaceIDs(record.Response, record.IDs, ids); err != nil {
+			return nil, fmt.Errorf("replayer : %s : %w", file, err)
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", record.StatusCode, http.StatusText(record.StatusCode)),
+		StatusCode:    record.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       r,
+	}, nil
+}
+
+// replaces the recorded json_rpc ids of a response by the ids of the replayed request, at the same position
+func replaceIDs(response json.RawMessage, recorded []interface{}, replayed []interface{}) ([]byte, error) {
+	if len(recorded) == 0 {
+		return response, nil
+	}
+	ids := make(map[string]interface{}, len(recorded))
+	for i, id := range recorded {
+		if i < len(replayed) {
+			ids[fmt.Sprint(id)] = replayed[i]
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	replace := func(envelope interface{}) {
+		if object, isObject := envelope.(map[string]interface{}); isObject {
+			if id, found := ids[fmt.Sprint(object["id"])]; found && object["id"] != nil {
+				object["id"] = id
+			}
+		}
+	}
+	if envelopes, isArray := value.([]interface{}); isArray {
+		for _, envelope := range envelopes {
+			replace(envelope)
+		}
+	} else {
+		replace(value)
+	}
+	return json.Marshal(value)
+}
+
+// WithRecording, records every exchange with the node into golden files of dir
+func WithRecording(dir string) Option {
+	return func(config *clientConfig) error {
+		config.wrap = func(next http.RoundTripper) http.RoundTripper { return NewRecorder(dir, next) }
+		return nil
+	}
+}
+
+// WithReplay, answers every request from the golden files of dir recorded by WithRecording, without network
+func WithReplay(dir string) Option {
+	return func(config *clientConfig) error {
+		if _, err := os.Stat(dir); err != nil {
+			return err
+		}
+		config.wrap = func(http.RoundTripper) http.RoundTripper { return NewReplayer(dir) }
+		return nil
+	}
+}
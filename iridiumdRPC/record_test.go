@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node traffic recording and replay tests
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
+)
+
+// calls recorded then replayed
+func recordedCalls(t *testing.T, node *Iridiumd) (uint32, *BlockHeader, *Transactions, []BlockHeader) {
+	count, err := node.BlockCount()
+	if err != nil {
+		t.Fatalf("%sGetBlockCount : %v", er, err)
+	}
+	header, err := node.BlockHeaderByHeight(3)
+	if err != nil {
+		t.Fatalf("%sGetBlockHeaderByHeight : %v", er, err)
+	}
+	txs, err := node.Transactions([]string{"unknown"})
+	if err != nil {
+		t.Fatalf("%sGetTransactions : %v", er, err)
+	}
+	headers := make([]BlockHeader, 2)
+	batch := node.NewBatch()
+	batch.BlockHeaderByHeight(1, &headers[0])
+	batch.BlockHeaderByHeight(2, &headers[1])
+	if err := batch.Send(context.Background()); err != nil {
+		t.Fatalf("%sbatch : %v", er, err)
+	}
+	return count, header, txs, headers
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iridiumd-golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	daemon := iridiumdtest.NewServer()
+	daemon.Mine(5)
+	recording, err := NewIridiumd(daemon.URL, WithRecording(dir))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	count, header, txs, headers := recordedCalls(t, recording)
+	daemon.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 4 {
+		t.Errorf("%s%d golden files, want 4 : %v", er, len(files), files)
+	}
+
+	// replayed without node, with other ids
+	replaying, err := NewIridiumd("http://127.0.0.1:1", WithReplay(dir), WithIDGenerator(UUIDIDs()))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	replayedCount, replayedHeader, replayedTxs, replayedHeaders := recordedCalls(t, replaying)
+	if replayedCount != count || *replayedHeader != *header || len(replayedTxs.MissedTx) != len(txs.MissedTx) ||
+		replayedHeaders[0] != headers[0] || replayedHeaders[1] != headers[1] {
+		t.Errorf("%sreplayed responses differ from the recorded ones", er)
+	}
+	t.Logf("%s%d exchanges replayed", ok, len(files))
+
+	// a request never recorded fails
+	if _, err := replaying.BlockHeaderByHeight(4); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("%swant %v, got %v", er, ErrNoRecording, err)
+	}
+	if _, err := NewIridiumd("http://127.0.0.1:1", WithReplay(filepath.Join(dir, "missing"))); err == nil {
+		t.Errorf("%sreplay of a missing directory accepted", er)
+	}
+}
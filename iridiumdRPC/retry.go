@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node requests retries
+
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy, retries of the idempotent read methods, methods submitting data are never retried
+type RetryPolicy struct {
+	// MaxAttempts, total number of attempts, 1 or less means no retry
+	MaxAttempts int
+	// BaseDelay, delay before the first retry, doubled at each retry
+	BaseDelay time.Duration
+	// MaxDelay, upper bound of the delay between two attempts, 0 means no bound
+	MaxDelay time.Duration
+	// Jitter, fraction of each delay which is randomized, between 0 and 1
+	Jitter float64
+	// Retryable, returns true when an error is worth a retry, IsRetryable when nil
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy, 4 attempts, 250ms, 500ms and 1s between them with 20% jitter
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WithRetry, retries the idempotent read methods according to policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(config *clientConfig) error {
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return errors.New("retry jitter must be between 0 and 1")
+		}
+		config.retry = &policy
+		return nil
+	}
+}
+
+/*
+IsRetryable, default retryable conditions : network errors and timeouts, http 5xx responses and busy nodes
+a cancelled or expired context, JSON RPC errors and invalid responses are not retried
+*/
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrNoRecording) {
+		return false
+	}
+	if errors.Is(err, ErrNodeBusy) {
+		return true
+	}
+	var httpError *HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.StatusCode >= 500
+	}
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// read methods which can be sent again safely, json_rpc methods and plain endpoints
+var idempotentMethods = map[string]bool{
+	"getheight":                   true,
+	"getinfo":                     true,
+	"gettransactions":             true,
+	"get_generated_coins":         true,
+	"getpeers":                    true,
+	"getblockcount":               true,
+	"getcurrencyid":               true,
+	"getlastblockheader":          true,
+	"getblockheaderbyhash":        true,
+	"getblockheaderbyheight":      true,
+	"on_getblockhash":             true,
+	"f_blocks_list_json":          true,
+	"f_block_json":                true,
+	"f_transaction_json":          true,
+	"f_on_transactions_pool_json": true,
+	"getblocktemplate":            true,
+}
+
+// IsIdempotent, true for the read methods retried by a retry policy
+func IsIdempotent(method string) bool {
+	return idempotentMethods[method]
+}
+
+// performs attempt, retried according to the node policy when the request is idempotent
+func (node *Iridiumd) withRetry(ctx context.Context, idempotent bool, attempt func() error) error {
+	if node.config == nil || node.config.retry == nil || !idempotent {
+		return attempt()
+	}
+	policy := node.config.retry
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	delay := policy.BaseDelay
+	for attempts := 1; ; attempts++ {
+		err := attempt()
+		if err == nil || attempts >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		wait := delay
+		if policy.Jitter > 0 {
+			wait = time.Duration(float64(delay) * (1 - policy.Jitter + rand.Float64()*policy.Jitter))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
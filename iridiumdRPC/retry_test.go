@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node requests retries tests
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fast policy for tests
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Jitter: 0.5}
+
+// handler failing with fail until the given number of attempts, then served by next
+func flakyHandler(failures int32, fail http.HandlerFunc, next http.HandlerFunc, attempts *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) <= failures {
+			fail(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func newRetryNode(t *testing.T, handler http.HandlerFunc) (*Iridiumd, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	node, err := NewIridiumd(server.URL, WithRetry(testRetryPolicy))
+	if err != nil {
+		server.Close()
+		t.Fatalf("%s %s", er, err)
+	}
+	return node, server
+}
+
+func TestRetry_ServerErrors(t *testing.T) {
+	var attempts int32
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}
+	node, server := newRetryNode(t, flakyHandler(2, unavailable, heightHandler, &attempts))
+	defer server.Close()
+
+	if _, err := node.Height(); err != nil || attempts != 3 {
+		t.Errorf("%sGetHeight after %d attempts : %v", er, attempts, err)
+	}
+
+	// attempts exhausted
+	atomic.StoreInt32(&attempts, -10)
+	var httpError *HTTPError
+	if _, err := node.Height(); !errors.As(err, &httpError) {
+		t.Errorf("%swant *HTTPError, got %v", er, err)
+	}
+	t.Logf("%sretried until success", ok)
+}
+
+func TestRetry_Busy(t *testing.T) {
+	var attempts int32
+	busy := echoHandler(`{"status": "BUSY"}`, "")
+	node, server := newRetryNode(t, flakyHandler(1, busy, echoHandler(`{"count": 357655, "status": "OK"}`, ""), &attempts))
+	defer server.Close()
+
+	if count, err := node.BlockCount(); err != nil || count != 357655 || attempts != 2 {
+		t.Errorf("%sGetBlockCount returns %d after %d attempts : %v", er, count, attempts, err)
+	}
+}
+
+func TestRetry_NotIdempotent(t *testing.T) {
+	var attempts int32
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}
+	node, server := newRetryNode(t, unavailable)
+	defer server.Close()
+
+	for _, method := range []string{"submitblock", "sendrawtransaction"} {
+		atomic.StoreInt32(&attempts, 0)
+		if err := node.Call(context.Background(), method, nil, nil); err == nil || attempts != 1 {
+			t.Errorf("%s%s sent %d times : %v", er, method, attempts, err)
+		}
+	}
+	atomic.StoreInt32(&attempts, 0)
+	if err := node.CallHTTP(context.Background(), "sendrawtransaction", nil, nil); err == nil || attempts != 1 {
+		t.Errorf("%s/sendrawtransaction sent %d times : %v", er, attempts, err)
+	}
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	var attempts int32
+	rpcError := echoHandler("", `{"code": -1, "message": "Wrong param"}`)
+	node, server := newRetryNode(t, flakyHandler(10, rpcError, rpcError, &attempts))
+	defer server.Close()
+
+	if _, err := node.BlockHeaderByHeight(0); err == nil || attempts != 1 {
+		t.Errorf("%sJSON RPC error retried %d times : %v", er, attempts, err)
+	}
+}
+
+func TestRetry_ContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "BUSY"}`))
+	}))
+	defer server.Close()
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}
+	node, err := NewIridiumd(server.URL, WithRetry(policy))
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := node.HeightContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("%swant %v, got %v", er, context.DeadlineExceeded, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{ErrNodeBusy, true},
+		{&RPCError{Code: ErrorCodeCoreBusy}, true},
+		{fmt.Errorf("%w : node at height 1, follower at 2", ErrNodeBehind), false},
+		{&HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{&HTTPError{StatusCode: http.StatusNotFound}, false},
+		{&RPCError{Code: ErrorCodeWrongParam}, false},
+		{fmt.Errorf("%w : sent 1, received 2", ErrIDMismatch), false},
+		{context.Canceled, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := IsRetryable(test.err); got != test.want {
+			t.Errorf("%sIsRetryable(%v) : want %t, got %t", er, test.err, test.want, got)
+		}
+	}
+}
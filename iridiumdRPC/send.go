@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium signed transactions broadcast
+
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+)
+
+// SendOption, option of SendRawTransaction
+type SendOption func(config *sendConfig)
+
+type sendConfig struct {
+	dryRun bool
+}
+
+// DryRun, SendRawTransaction decodes and summarizes the transaction without sending it to the node
+func DryRun() SendOption {
+	return func(config *sendConfig) {
+		config.dryRun = true
+	}
+}
+
+/*
+/sendrawtransaction, broadcasts a signed transaction, hex encoded, and returns its summary
+the transaction is decoded locally first, an invalid one matches cryptonote.ErrInvalidTransaction and is not sent,
+a transaction refused by the node returns a *TxRejectedError, matching ErrTxRejected and, best-effort, a reason :
+ErrTxNotRelayed, or ErrDoubleSpend, ErrFeeTooLow, ErrTxTooBig when the node status details it, a stock daemon doesn't
+sendrawtransaction is never retried
+*/
+func (node *Iridiumd) SendRawTransaction(ctx context.Context, txHex string, options ...SendOption) (*TransactionSummary, error) {
+	var config sendConfig
+	for _, option := range options {
+		option(&config)
+	}
+	summary, err := SummarizeTransaction(txHex)
+	if err != nil || config.dryRun {
+		return summary, err
+	}
+
+	payload := make(map[string]interface{})
+	payload["tx_as_hex"] = txHex
+	body, err := node.makeGetRequest(ctx, "sendrawtransaction", payload)
+	if err != nil {
+		return nil, err
+	}
+	var result statusResult
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.Status != "OK" {
+		return nil, rejection(result.Status)
+	}
+	return summary, nil
+}
+
+/*
+SummarizeTransaction, decodes a signed transaction, hex encoded, into the f_transaction_json summary :
+hash, fee (inputs minus outputs), amount out, size, mixin (smallest ring size minus 1) and payment id,
+a transaction without key input, spending more than its inputs or with amounts over the largest Amount is invalid
+*/
+func SummarizeTransaction(txHex string) (*TransactionSummary, error) {
+	blob, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", cryptonote.ErrInvalidTransaction, err)
+	}
+	tx, err := cryptonote.DecodeTransaction(blob)
+	if err != nil {
+		return nil, err
+	}
+	hash := tx.Hash()
+	summary := &TransactionSummary{Hash: hex.EncodeToString(hash[:]), Size: uint64(len(blob))}
+
+	// amounts are summed unsigned, checked for overflow before being compared
+	var amountIn, amountOut uint64
+	ring := -1
+	for _, input := range tx.Inputs {
+		if input.Type != cryptonote.InputKey {
+			return nil, fmt.Errorf("%w : input of type %02x", cryptonote.ErrInvalidTransaction, input.Type)
+		}
+		if amountIn+input.Amount < amountIn {
+			return nil, fmt.Errorf("%w : inputs amount overflow", cryptonote.ErrInvalidTransaction)
+		}
+		amountIn += input.Amount
+		if ring < 0 || len(input.KeyOffsets) < ring {
+			ring = len(input.KeyOffsets)
+		}
+	}
+	if ring <= 0 {
+		return nil, fmt.Errorf("%w : no key input, or one without key offsets", cryptonote.ErrInvalidTransaction)
+	}
+	if amountIn > math.MaxInt64 {
+		return nil, fmt.Errorf("%w : inputs amount %d over the largest amount", cryptonote.ErrInvalidTransaction, amountIn)
+	}
+	for _, output := range tx.Outputs {
+		if amountOut+output.Amount < amountOut {
+			return nil, fmt.Errorf("%w : outputs amount overflow", cryptonote.ErrInvalidTransaction)
+		}
+		amountOut += output.Amount
+	}
+	if amountOut > amountIn {
+		return nil, fmt.Errorf("%w : outputs %s over inputs %s", cryptonote.ErrInvalidTransaction, Amount(amountOut), Amount(amountIn))
+	}
+	summary.AmountOut, summary.Fee = Amount(amountOut), Amount(amountIn-amountOut)
+	summary.Mixin = uint64(ring - 1)
+
+	// the payment id is informative, a malformed extra is left to the node
+	if extra, _ := cryptonote.ParseExtra(tx.Extra); extra != nil && extra.PaymentID != nil {
+		summary.PaymentID = extra.PaymentID.String()
+	}
+	return summary, nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium signed transactions broadcast tests
+package iridiumdRPC
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/steevebrush/iridium-go/iridiumdRPC/address"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/cryptonote"
+	"github.com/steevebrush/iridium-go/iridiumdRPC/iridiumdtest"
+)
+
+// a signed transaction spending image, with a ring of 4 and a payment id, signatures are not checked
+func signedTransaction(image byte, amountIn uint64, amountsOut ...uint64) string {
+	outputs := make([]cryptonote.Output, len(amountsOut))
+	for i, amount := range amountsOut {
+		outputs[i] = cryptonote.Output{Amount: amount, Key: address.PublicKey{byte(i + 1)}}
+	}
+	tx := cryptonote.Transaction{
+		TransactionPrefix: cryptonote.TransactionPrefix{
+			Version: 1,
+			Inputs: []cryptonote.Input{{
+				Type:       cryptonote.InputKey,
+				Amount:     amountIn,
+				KeyOffsets: []uint32{12, 5, 30, 2},
+				KeyImage:   cryptonote.KeyImage{image},
+			}},
+			Outputs: outputs,
+			Extra:   append([]byte{cryptonote.ExtraTagNonce, 33, cryptonote.ExtraNoncePaymentID}, make([]byte, address.PaymentIDSize)...),
+		},
+		Signatures: [][]cryptonote.Signature{make([]cryptonote.Signature, 4)},
+	}
+	return hex.EncodeToString(tx.Bytes())
+}
+
+func TestSummarizeTransaction(t *testing.T) {
+	txHex := signedTransaction(1, 500000000, 499000000)
+	summary, err := SummarizeTransaction(txHex)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	blob, _ := hex.DecodeString(txHex)
+	decoded, _ := cryptonote.DecodeTransaction(blob)
+	hash := decoded.Hash()
+	if summary.Hash != hex.EncodeToString(hash[:]) || summary.Fee != 1000000 || summary.AmountOut != 499000000 ||
+		summary.Size != uint64(len(blob)) || summary.Mixin != 3 || summary.PaymentID != (address.PaymentID{}).String() {
+		t.Errorf("%ssummary %+v", er, summary)
+	}
+	t.Logf("%s%+v", ok, *summary)
+
+	for name, invalid := range map[string]string{
+		"not hex":                        "zz",
+		"truncated":                      txHex[:40],
+		"over spending":                  signedTransaction(1, 500000000, 600000000),
+		"inputs over the largest amount": signedTransaction(1, math.MaxInt64+1, 1),
+		"outputs overflow":               signedTransaction(1, 500000000, math.MaxUint64, 2),
+		"coinbase":                       coinbaseBlob,
+	} {
+		if _, err := SummarizeTransaction(invalid); !errors.Is(err, cryptonote.ErrInvalidTransaction) {
+			t.Errorf("%s%s : want %v, got %v", er, name, cryptonote.ErrInvalidTransaction, err)
+		}
+	}
+}
+
+func TestIridiumd_SendRawTransaction(t *testing.T) {
+	daemon := iridiumdtest.NewServer()
+	defer daemon.Close()
+	sender, err := NewIridiumd(daemon.URL)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	ctx := context.Background()
+	txHex := signedTransaction(1, 500000000, 499000000)
+
+	// a dry run doesn't reach the node
+	summary, err := sender.SendRawTransaction(ctx, txHex, DryRun())
+	if err != nil || summary.Fee != 1000000 {
+		t.Fatalf("%sdry run : %+v, %v", er, summary, err)
+	}
+	if len(daemon.Requests()) != 0 {
+		t.Errorf("%sdry run sent %v", er, daemon.Requests())
+	}
+
+	sent, err := sender.SendRawTransaction(ctx, txHex)
+	if err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if *sent != *summary {
+		t.Errorf("%ssent %+v, dry run %+v", er, sent, summary)
+	}
+	pool, err := sender.TransactionsPool()
+	if err != nil || len(pool) != 1 || pool[0].Hash != sent.Hash || pool[0].Fee != sent.Fee {
+		t.Errorf("%spool %+v, %v", er, pool, err)
+	}
+	t.Logf("%s%s sent", ok, sent.Hash)
+
+	// the daemon answers "Failed" without reason, a transaction already in the pool is not relayed
+	rejected := map[string]string{
+		"Failed":      signedTransaction(1, 600000000, 599000000),
+		"Not relayed": txHex,
+	}
+	for status, txHex := range rejected {
+		var rejection *TxRejectedError
+		_, err := sender.SendRawTransaction(ctx, txHex)
+		if !errors.Is(err, ErrTxRejected) || !errors.As(err, &rejection) || rejection.Status != status {
+			t.Errorf("%swant %q, got %v", er, status, err)
+		}
+	}
+	low := signedTransaction(2, 500000000, 500000000-iridiumdtest.MinimumFee+1)
+	if _, err := sender.SendRawTransaction(ctx, low); !errors.Is(err, ErrTxRejected) || errors.Unwrap(err) != nil {
+		t.Errorf("%sfee too low : want %v without reason, got %v", er, ErrTxRejected, err)
+	}
+	if _, err := sender.SendRawTransaction(ctx, txHex); !errors.Is(err, ErrTxNotRelayed) {
+		t.Errorf("%swant %v, got %v", er, ErrTxNotRelayed, err)
+	}
+
+	// checked before being sent
+	sentCount := len(daemon.Requests())
+	if _, err := sender.SendRawTransaction(ctx, "0102"); !errors.Is(err, cryptonote.ErrInvalidTransaction) {
+		t.Errorf("%swant %v, got %v", er, cryptonote.ErrInvalidTransaction, err)
+	}
+	if len(daemon.Requests()) != sentCount {
+		t.Errorf("%san invalid transaction was sent", er)
+	}
+}
+
+func TestTxRejectedError(t *testing.T) {
+	for status, reason := range map[string]error{
+		"Failed : double spend":                 ErrDoubleSpend,
+		"Failed : key image already spent":      ErrDoubleSpend,
+		"Failed : transaction fee is too small": ErrFeeTooLow,
+		"Failed : transaction is too big":       ErrTxTooBig,
+		"Not relayed":                           ErrTxNotRelayed,
+		" not relayed ":                         ErrTxNotRelayed,
+		"Failed":                                nil,
+		"Failed : fee too high":                 nil,
+		"Failed : unspent output not found":     nil,
+		"Failed : not relayed yet":              nil,
+	} {
+		err := rejection(status)
+		if !errors.Is(err, ErrTxRejected) || errors.Unwrap(err) != reason {
+			t.Errorf("%s%q : want %v, got %v", er, status, reason, errors.Unwrap(err))
+		}
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transactions confirmation tracker
+
+package iridiumdRPC
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TxState, state of a tracked transaction
+type TxState int
+
+const (
+	// TxUnknown, the transaction hasn't been seen by the node yet
+	TxUnknown TxState = iota
+	// TxPending, the transaction is in the pool
+	TxPending
+	// TxIncluded, the transaction is in the block at Height, with less confirmations than required
+	TxIncluded
+	// TxConfirmed, the transaction has the required confirmations, it is no longer tracked
+	TxConfirmed
+	// TxDropped, the transaction was seen but the node doesn't know it anymore, it is no longer tracked
+	TxDropped
+	// TxReorged, the block including the transaction left the chain
+	TxReorged
+)
+
+func (state TxState) String() string {
+	switch state {
+	case TxUnknown:
+		return "unknown"
+	case TxPending:
+		return "pending"
+	case TxIncluded:
+		return "included"
+	case TxConfirmed:
+		return "confirmed"
+	case TxDropped:
+		return "dropped"
+	case TxReorged:
+		return "reorged"
+	}
+	return "unknown state " + strconv.Itoa(int(state))
+}
+
+// TxStatus, state change of a tracked transaction, block fields are set for TxIncluded, TxConfirmed and TxReorged
+type TxStatus struct {
+	Hash          string
+	State         TxState
+	Height        uint32
+	BlockHash     string
+	Confirmations uint32
+}
+
+// a tracked transaction
+type trackedTx struct {
+	depth  uint32
+	status TxStatus
+}
+
+/*
+TxTracker, follows transactions until they reach their confirmations depth, reporting their state changes,
+each poll looks up all the tracked transactions in one batch with f_transaction_json,
+a tracker isn't safe for concurrent use
+*/
+type TxTracker struct {
+	node         *Iridiumd
+	pollInterval time.Duration
+	tracked      map[string]*trackedTx
+}
+
+// NewTxTracker, returns a tracker of transactions of the node polling every pollInterval, 10 seconds when 0
+func NewTxTracker(node *Iridiumd, pollInterval time.Duration) *TxTracker {
+	if pollInterval == 0 {
+		pollInterval = 10 * time.Second
+	}
+	return &TxTracker{node: node, pollInterval: pollInterval, tracked: make(map[string]*trackedTx)}
+}
+
+// Track, follows the transaction hash until it has depth confirmations, at least 1
+func (tracker *TxTracker) Track(hash string, depth uint32) {
+	if depth == 0 {
+		depth = 1
+	}
+	if tx, found := tracker.tracked[hash]; found {
+		tx.depth = depth
+		return
+	}
+	tracker.tracked[hash] = &trackedTx{depth: depth, status: TxStatus{Hash: hash}}
+}
+
+// Untrack, stops following the transaction hash
+func (tracker *TxTracker) Untrack(hash string) {
+	delete(tracker.tracked, hash)
+}
+
+// Len, number of tracked transactions
+func (tracker *TxTracker) Len() int {
+	return len(tracker.tracked)
+}
+
+/*
+Run, polls the node every poll interval and sends the state changes to handle until ctx is done,
+retryable node errors wait for the next poll, other errors and handle errors stop Run
+*/
+func (tracker *TxTracker) Run(ctx context.Context, handle func(status TxStatus) error) error {
+	ticker := time.NewTicker(tracker.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := tracker.Poll(ctx, handle); err != nil && (!IsRetryable(err) || ctx.Err() != nil) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Poll, looks up the tracked transactions and sends their state changes to handle,
+a transaction whose block left the chain is first reported TxReorged, then with its new state,
+a transaction is only dropped when the node answers it wasn't found, other node errors are returned,
+a state change refused by handle is sent again at the next poll
+*/
+func (tracker *TxTracker) Poll(ctx context.Context, handle func(status TxStatus) error) error {
+	if len(tracker.tracked) == 0 {
+		return nil
+	}
+	hashes := make([]string, 0, len(tracker.tracked))
+	for hash := range tracker.tracked {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	batch := tracker.node.NewBatch()
+	var count blockCountResult
+	countCall := batch.Queue("getblockcount", nil, &count)
+	details := make([]TransactionDetails, len(hashes))
+	calls := make([]*BatchCall, len(hashes))
+	for i, hash := range hashes {
+		calls[i] = batch.TransactionDetails(hash, &details[i])
+	}
+	if err := batch.Send(ctx); err != nil {
+		return err
+	}
+	if countCall.Error != nil {
+		return countCall.Error
+	}
+
+	for i, hash := range hashes {
+		tx := tracker.tracked[hash]
+		status := TxStatus{Hash: hash, State: TxDropped}
+		var rpcError *RPCError
+		switch {
+		case calls[i].Error == nil && details[i].Block.Hash == "":
+			status.State = TxPending
+		case calls[i].Error == nil:
+			block := details[i].Block
+			status.State, status.Height, status.BlockHash = TxIncluded, block.Height, block.Hash
+			if count.Count > block.Height {
+				status.Confirmations = count.Count - block.Height
+			}
+			if status.Confirmations >= tx.depth {
+				status.State = TxConfirmed
+			}
+		case !errors.As(calls[i].Error, &rpcError) || rpcError.Code != ErrorCodeWrongParam:
+			// only "transaction wasn't found" means the node doesn't know it, a busy node is retried by Run
+			return calls[i].Error
+		case tx.status.State == TxUnknown:
+			// not seen yet
+			continue
+		}
+		if err := tracker.update(tx, status, handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reports the new status of a transaction if its state changed
+func (tracker *TxTracker) update(tx *trackedTx, status TxStatus, handle func(status TxStatus) error) error {
+	previous := tx.status
+	if previous.State == status.State && previous.BlockHash == status.BlockHash {
+		tx.status.Confirmations = status.Confirmations
+		return nil
+	}
+	if previous.State == TxIncluded && previous.BlockHash != status.BlockHash {
+		reorged := previous
+		reorged.State = TxReorged
+		if err := handle(reorged); err != nil {
+			return err
+		}
+		tx.status = TxStatus{Hash: previous.Hash, State: TxReorged}
+	}
+	if err := handle(status); err != nil {
+		return err
+	}
+	tx.status = status
+	if status.State == TxConfirmed || status.State == TxDropped {
+		delete(tracker.tracked, status.Hash)
+	}
+	return nil
+}
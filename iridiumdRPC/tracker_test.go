@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium transactions confirmation tracker tests
+package iridiumdRPC
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fake node knowing transactions in the pool (height -1) or in blocks
+type testTxs struct {
+	mu      sync.Mutex
+	count   int
+	heights map[string]int
+	branch  string
+	// error code answered to f_transaction_json when set
+	failure int
+}
+
+func (txs *testTxs) set(hash string, height int) {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	if height < -1 {
+		delete(txs.heights, hash)
+		return
+	}
+	txs.heights[hash] = height
+}
+
+func (txs *testTxs) answer(request testRequest) map[string]interface{} {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if request.Method == "getblockcount" {
+		response["result"] = map[string]interface{}{"count": txs.count, "status": "OK"}
+		return response
+	}
+	if txs.failure != 0 {
+		response["error"] = map[string]interface{}{"code": txs.failure, "message": "failure"}
+		return response
+	}
+	hash := request.Params["hash"].(string)
+	height, found := txs.heights[hash]
+	if !found {
+		response["error"] = map[string]interface{}{"code": ErrorCodeWrongParam, "message": "transaction wasn't found. Hash = " + hash}
+		return response
+	}
+	block := map[string]interface{}{}
+	if height >= 0 {
+		block = map[string]interface{}{"height": height, "hash": fmt.Sprintf("%s%08d", txs.branch, height)}
+	}
+	response["result"] = map[string]interface{}{"block": block, "tx": map[string]interface{}{}, "status": "OK"}
+	return response
+}
+
+// answers single requests and the batches of the tracker polls
+func (txs *testTxs) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		var requests []testRequest
+		json.Unmarshal(body, &requests)
+		responses := make([]map[string]interface{}, len(requests))
+		for i, request := range requests {
+			responses[i] = txs.answer(request)
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+	var request testRequest
+	json.Unmarshal(body, &request)
+	json.NewEncoder(w).Encode(txs.answer(request))
+}
+
+func TestTxTracker(t *testing.T) {
+	txs := &testTxs{count: 100, heights: make(map[string]int), branch: "main"}
+	var requests int32
+	node, server := newHandlerNode(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		txs.handler(w, r)
+	})
+	defer server.Close()
+
+	tracker := NewTxTracker(node, 0)
+	tracker.Track("tx1", 3)
+	tracker.Track("tx2", 3)
+	tracker.Track("tx3", 1)
+	txs.set("tx1", -1)
+	txs.set("tx3", -1)
+
+	var events []string
+	poll := func(want ...string) {
+		t.Helper()
+		events = nil
+		err := tracker.Poll(context.Background(), func(status TxStatus) error {
+			events = append(events, fmt.Sprintf("%s %s %s %d", status.Hash, status.State, status.BlockHash, status.Confirmations))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%sPoll : %v", er, err)
+		}
+		checkEvents(t, events, want...)
+	}
+
+	poll("tx1 pending  0", "tx3 pending  0")
+
+	// tx1 and tx2 mined, tx3 dropped
+	txs.set("tx1", 100)
+	txs.set("tx2", 100)
+	txs.set("tx3", -2)
+	txs.count = 101
+	poll("tx1 included main00000100 1", "tx2 included main00000100 1", "tx3 dropped  0")
+
+	// reorganization, tx1 back in the pool, tx2 mined in the new block
+	txs.branch = "alt"
+	txs.set("tx1", -1)
+	poll("tx1 reorged main00000100 1", "tx1 pending  0", "tx2 reorged main00000100 1", "tx2 included alt00000100 1")
+
+	txs.count = 103
+	before := atomic.LoadInt32(&requests)
+	poll("tx2 confirmed alt00000100 3")
+	if sent := atomic.LoadInt32(&requests) - before; sent != 1 || tracker.Len() != 1 {
+		t.Errorf("%s%d requests for one poll, %d tracked", er, sent, tracker.Len())
+	}
+	t.Logf("%sstate changes reported in one batch", ok)
+}
+
+func TestTxTracker_NodeErrors(t *testing.T) {
+	txs := &testTxs{count: 100, heights: map[string]int{"tx1": -1}, branch: "main"}
+	node, server := newHandlerNode(t, txs.handler)
+	defer server.Close()
+	tracker := NewTxTracker(node, 0)
+	tracker.Track("tx1", 3)
+	var events []string
+	handle := func(status TxStatus) error {
+		events = append(events, fmt.Sprintf("%s %s", status.Hash, status.State))
+		return nil
+	}
+	if err := tracker.Poll(context.Background(), handle); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+
+	// the node fails, the transaction isn't dropped
+	for code, retryable := range map[int]bool{ErrorCodeCoreBusy: true, ErrorCodeInternalError: false} {
+		txs.failure = code
+		err := tracker.Poll(context.Background(), handle)
+		var rpcError *RPCError
+		if !errors.As(err, &rpcError) || rpcError.Code != code || IsRetryable(err) != retryable {
+			t.Errorf("%scode %d : got %v, retryable %t", er, code, err, IsRetryable(err))
+		}
+	}
+	txs.failure = 0
+	if err := tracker.Poll(context.Background(), handle); err != nil || tracker.Len() != 1 {
+		t.Errorf("%s%d tracked, %v", er, tracker.Len(), err)
+	}
+	checkEvents(t, events, "tx1 pending")
+	t.Logf("%snode errors returned, %q", ok, events)
+}
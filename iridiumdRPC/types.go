@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API typed responses
+
+package iridiumdRPC
+
+import "encoding/json"
+
+// JSON RPC 2.0 envelope returned by /json_rpc
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Result  json.RawMessage `json:"result"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// NodeHeight, /getheight response
+type NodeHeight struct {
+	Height        uint32 `json:"height"`
+	NetworkHeight uint32 `json:"network_height"`
+	Status        string `json:"status"`
+}
+
+// NodeInfo, /getinfo response
+type NodeInfo struct {
+	AltBlocksCount           uint64 `json:"alt_blocks_count"`
+	Difficulty               uint64 `json:"difficulty"`
+	GreyPeerlistSize         uint64 `json:"grey_peerlist_size"`
+	Height                   uint32 `json:"height"`
+	IncomingConnectionsCount uint64 `json:"incoming_connections_count"`
+	LastKnownBlockIndex      uint32 `json:"last_known_block_index"`
+	OutgoingConnectionsCount uint64 `json:"outgoing_connections_count"`
+	Status                   string `json:"status"`
+	Synced                   bool   `json:"synced"`
+	TxCount                  uint64 `json:"tx_count"`
+	TxPoolSize               uint64 `json:"tx_pool_size"`
+	Version                  string `json:"version"`
+	WhitePeerlistSize        uint64 `json:"white_peerlist_size"`
+}
+
+// Transactions, /gettransactions response
+type Transactions struct {
+	TxsAsHex []string `json:"txs_as_hex"`
+	MissedTx []string `json:"missed_tx"`
+	Status   string   `json:"status"`
+}
+
+// GeneratedCoins, /get_generated_coins response
+type GeneratedCoins struct {
+	AlreadyGeneratedCoins Amount `json:"alreadyGeneratedCoins"`
+	Status                string `json:"status"`
+}
+
+// BlockHeader, block_header object of getlastblockheader, getblockheaderbyhash and getblockheaderbyheight
+type BlockHeader struct {
+	MajorVersion uint8  `json:"major_version"`
+	MinorVersion uint8  `json:"minor_version"`
+	Timestamp    uint64 `json:"timestamp"`
+	PrevHash     string `json:"prev_hash"`
+	Nonce        uint32 `json:"nonce"`
+	OrphanStatus bool   `json:"orphan_status"`
+	Height       uint32 `json:"height"`
+	Depth        uint32 `json:"depth"`
+	Hash         string `json:"hash"`
+	Difficulty   uint64 `json:"difficulty"`
+	Reward       Amount `json:"reward"`
+}
+
+// BlockShort, block summary of f_blocks_list_json and f_transaction_json
+type BlockShort struct {
+	CumulSize  uint64 `json:"cumul_size"`
+	Difficulty uint64 `json:"difficulty"`
+	Hash       string `json:"hash"`
+	Height     uint32 `json:"height"`
+	Reward     Amount `json:"reward"`
+	Timestamp  uint64 `json:"timestamp"`
+	TxCount    uint32 `json:"tx_count"`
+}
+
+// TransactionShort, transaction summary of f_block_json
+type TransactionShort struct {
+	Hash      string `json:"hash"`
+	Fee       Amount `json:"fee"`
+	AmountOut Amount `json:"amount_out"`
+	Size      uint64 `json:"size"`
+}
+
+// BlockDetails, f_block_json block object
+type BlockDetails struct {
+	MajorVersion                 uint8              `json:"major_version"`
+	MinorVersion                 uint8              `json:"minor_version"`
+	Timestamp                    uint64             `json:"timestamp"`
+	PrevHash                     string             `json:"prev_hash"`
+	Nonce                        uint32             `json:"nonce"`
+	OrphanStatus                 bool               `json:"orphan_status"`
+	Height                       uint32             `json:"height"`
+	Depth                        uint32             `json:"depth"`
+	Hash                         string             `json:"hash"`
+	Difficulty                   uint64             `json:"difficulty"`
+	Reward                       Amount             `json:"reward"`
+	BlockSize                    uint64             `json:"blockSize"`
+	SizeMedian                   uint64             `json:"sizeMedian"`
+	EffectiveSizeMedian          uint64             `json:"effectiveSizeMedian"`
+	TransactionsCumulativeSize   uint64             `json:"transactionsCumulativeSize"`
+	AlreadyGeneratedCoins        Amount             `json:"alreadyGeneratedCoins"`
+	AlreadyGeneratedTransactions uint64             `json:"alreadyGeneratedTransactions"`
+	BaseReward                   Amount             `json:"baseReward"`
+	Penalty                      float64            `json:"penalty"`
+	TotalFeeAmount               Amount             `json:"totalFeeAmount"`
+	Transactions                 []TransactionShort `json:"transactions"`
+}
+
+// TransactionInputValue, value of a transaction input,
+// gen inputs ("ff") only have a height, key inputs ("02") an amount, key offsets and a key image
+type TransactionInputValue struct {
+	Height     uint32   `json:"height,omitempty"`
+	Amount     Amount   `json:"amount,omitempty"`
+	KeyOffsets []uint32 `json:"key_offsets,omitempty"`
+	KeyImage   string   `json:"k_image,omitempty"`
+}
+
+// TransactionInput, vin entry of a transaction
+type TransactionInput struct {
+	Type  string                `json:"type"`
+	Value TransactionInputValue `json:"value"`
+}
+
+// TransactionOutputTarget, target of a transaction output, "02" is a key output
+type TransactionOutputTarget struct {
+	Type string `json:"type"`
+	Data struct {
+		Key string `json:"key"`
+	} `json:"data"`
+}
+
+// TransactionOutput, vout entry of a transaction
+type TransactionOutput struct {
+	Amount Amount                  `json:"amount"`
+	Target TransactionOutputTarget `json:"target"`
+}
+
+// Transaction, transaction prefix as shown by f_transaction_json, extra is hex encoded
+type Transaction struct {
+	Version    uint8               `json:"version"`
+	UnlockTime uint64              `json:"unlock_time"`
+	Vin        []TransactionInput  `json:"vin"`
+	Vout       []TransactionOutput `json:"vout"`
+	Extra      string              `json:"extra"`
+}
+
+// TransactionSummary, txDetails object of f_transaction_json
+type TransactionSummary struct {
+	Hash      string `json:"hash"`
+	Fee       Amount `json:"fee"`
+	AmountOut Amount `json:"amount_out"`
+	Size      uint64 `json:"size"`
+	Mixin     uint64 `json:"mixin"`
+	PaymentID string `json:"paymentId"`
+}
+
+// TransactionDetails, f_transaction_json result : the transaction, its summary and the block including it
+type TransactionDetails struct {
+	Block     BlockShort         `json:"block"`
+	Tx        Transaction        `json:"tx"`
+	TxDetails TransactionSummary `json:"txDetails"`
+	Status    string             `json:"status"`
+}
+
+// PoolTransaction, transaction summary of f_on_transactions_pool_json
+type PoolTransaction struct {
+	Hash        string `json:"hash"`
+	Fee         Amount `json:"fee"`
+	AmountOut   Amount `json:"amount_out"`
+	Size        uint64 `json:"size"`
+	ReceiveTime uint64 `json:"receive_time"`
+}
+
+// BlockTemplate, getblocktemplate result : the blob of the block to mine, the miner fills reserve_size bytes at reserved_offset
+type BlockTemplate struct {
+	BlocktemplateBlob string `json:"blocktemplate_blob"`
+	Difficulty        uint64 `json:"difficulty"`
+	Height            uint32 `json:"height"`
+	ReservedOffset    uint32 `json:"reserved_offset"`
+	Status            string `json:"status"`
+}
+
+// json_rpc results wrapping the typed objects above
+
+type blockCountResult struct {
+	Count  uint32 `json:"count"`
+	Status string `json:"status"`
+}
+
+type currencyIDResult struct {
+	CurrencyIDBlob string `json:"currency_id_blob"`
+}
+
+type blockHeaderResult struct {
+	BlockHeader BlockHeader `json:"block_header"`
+	Status      string      `json:"status"`
+}
+
+type blocksListResult struct {
+	Blocks []BlockShort `json:"blocks"`
+	Status string       `json:"status"`
+}
+
+type blockDetailsResult struct {
+	Block  BlockDetails `json:"block"`
+	Status string       `json:"status"`
+}
+
+type transactionsPoolResult struct {
+	Transactions []PoolTransaction `json:"transactions"`
+	Status       string            `json:"status"`
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2019.
+ * by Steve Brush, Iridium Developers
+ */
+
+// Iridium node JSON RPC API typed responses tests
+package iridiumdRPC
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// f_transaction_json result as sent by a node
+const transactionDetailsJSON = `{
+  "block": {"cumul_size": 8430, "difficulty": 475677472, "hash": "f0c002e703d8dc19f6a5ca844805015213f223f3a52a258d3fa22a69e8177213", "height": 357782, "timestamp": 1567540598, "tx_count": 7},
+  "status": "OK",
+  "tx": {
+    "extra": "01e3a1b6040ba307633583398b8f59f37d71f643e8c06ad761a546d0feeeccccad021100000000e53a8eb3000000000000000000",
+    "unlock_time": 357802, "version": 1,
+    "vin": [{"type": "ff", "value": {"height": 357782}}],
+    "vout": [{"amount": 241, "target": {"data": {"key": "5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58"}, "type": "02"}}]
+  },
+  "txDetails": {"amount_out": 2437609241, "fee": 0, "hash": "ba29fad80ab5eb6741bac01e5326f7c28ced3238c3d7bce1abbd97180aa20ec2", "mixin": 0, "paymentId": "", "size": 319}
+}`
+
+// f_block_json block as sent by a node, alreadyGeneratedCoins is a string
+const blockDetailsJSON = `{"block": {"alreadyGeneratedCoins": "9536743164", "alreadyGeneratedTransactions": 1, "baseReward": 9536743164, "blockSize": 118, "depth": 357785, "difficulty": 1, "effectiveSizeMedian": 20000, "hash": "9d59c3ac5acc80eef180cc15c6cd49febfc7f7131ed38b080c8436021b9caf43", "height": 0, "major_version": 1, "minor_version": 0, "nonce": 70, "orphan_status": false, "penalty": 0.0, "prev_hash": "0000000000000000000000000000000000000000000000000000000000000000", "reward": 9536743164, "sizeMedian": 0, "timestamp": 0, "totalFeeAmount": 0, "transactions": [{"amount_out": 9536743164, "fee": 0, "hash": "f3fe271b4edceebf60a29d535a8dec957809baf4c69549a09ae113eb88a5f1ad", "size": 78}], "transactionsCumulativeSize": 78}, "status": "OK"}`
+
+func TestTransactionDetails_Decode(t *testing.T) {
+	var details TransactionDetails
+	if err := json.Unmarshal([]byte(transactionDetailsJSON), &details); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	if details.Block.Height != 357782 || details.TxDetails.AmountOut != 2437609241 {
+		t.Errorf("%sTransactionDetails decoded as %+v", er, details)
+	}
+	if len(details.Tx.Vin) != 1 || details.Tx.Vin[0].Type != "ff" || details.Tx.Vin[0].Value.Height != 357782 {
+		t.Errorf("%sgen input decoded as %+v", er, details.Tx.Vin)
+	}
+	if len(details.Tx.Vout) != 1 || details.Tx.Vout[0].Target.Data.Key != "5f7a83a576e401ad0c1a10b0b9050be3e02640604be5ddca986693636bf84a58" {
+		t.Errorf("%soutputs decoded as %+v", er, details.Tx.Vout)
+	}
+	t.Logf("%sTransactionDetails decoded", ok)
+}
+
+func TestBlockDetails_Decode(t *testing.T) {
+	var result blockDetailsResult
+	if err := json.Unmarshal([]byte(blockDetailsJSON), &result); err != nil {
+		t.Fatalf("%s %s", er, err)
+	}
+	block := result.Block
+	if block.AlreadyGeneratedCoins != 9536743164 || block.Reward != 9536743164 || block.Nonce != 70 {
+		t.Errorf("%sBlockDetails decoded as %+v", er, block)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Size != 78 {
+		t.Errorf("%stransactions decoded as %+v", er, block.Transactions)
+	}
+	t.Logf("%sBlockDetails decoded", ok)
+}